@@ -0,0 +1,183 @@
+package emission
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wsshow/op/deque"
+	"github.com/wsshow/op/workerpool"
+)
+
+// ErrQueueFull 表示分发队列或监听器邮箱已满，监听器调用被拒绝
+// 仅在溢出策略为 OverflowError 时由 Emit 与 EmitWait 返回
+var ErrQueueFull = errors.New("emission: dispatch queue is full")
+
+// OverflowPolicy 定义分发队列已满时的处理策略
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 阻塞 Emit 直到队列有空位（默认）
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop 丢弃无法入队的监听器调用，并计入 Dropped
+	OverflowDrop
+	// OverflowError 丢弃无法入队的监听器调用，计入 Dropped 并返回 ErrQueueFull
+	OverflowError
+)
+
+// dispatcher 是 Emitter 所有事件共享的异步分发队列
+// 每个监听器调用是一个任务，同时执行的任务数不超过 limit，等待执行的任务数不超过 queueSize
+// 未设置协程池时按需创建工作协程，队列为空时工作协程退出，因此空闲的 Emitter 不持有协程
+type dispatcher struct {
+	mu        sync.Mutex
+	space     *sync.Cond // 队列出现空位时通知阻塞的提交方
+	queue     *deque.Deque[func()]
+	limit     int            // 最大并发数，<= 0 表示无限制
+	queueSize int            // 最大等待任务数，<= 0 表示无限制
+	policy    OverflowPolicy // 队列已满时的处理策略
+	running   int            // 存活的工作协程数
+	pool      *workerpool.WorkerPool
+	pending   int // 已提交到协程池但尚未开始执行的任务数
+	dropped   atomic.Uint64
+}
+
+// newDispatcher 创建无并发限制、无队列上限的分发队列
+func newDispatcher() *dispatcher {
+	d := &dispatcher{queue: deque.New[func()]()}
+	d.space = sync.NewCond(&d.mu)
+	return d
+}
+
+// submit 提交一个任务，按溢出策略处理队列已满的情况
+func (d *dispatcher) submit(task func()) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for {
+		if d.pool != nil {
+			if d.queueSize <= 0 || d.pending < d.queueSize {
+				err := d.submitPool(task)
+				if err != nil {
+					d.dropped.Add(1)
+				}
+				return err
+			}
+		} else {
+			if d.limit <= 0 || d.running < d.limit {
+				d.running++
+				go d.work(task)
+				return nil
+			}
+			if d.queueSize <= 0 || d.queue.Size() < d.queueSize {
+				d.queue.PushBack(task)
+				return nil
+			}
+		}
+
+		switch d.policy {
+		case OverflowDrop:
+			d.dropped.Add(1)
+			return nil
+		case OverflowError:
+			d.dropped.Add(1)
+			return ErrQueueFull
+		default:
+			d.space.Wait()
+		}
+	}
+}
+
+// run 提交一个不受队列容量限制的任务，用于邮箱的排空任务
+// 排空任务的数量不超过监听器数量，并且不能被丢弃，否则邮箱中的投递将无法执行
+func (d *dispatcher) run(task func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case d.pool != nil:
+		if d.submitPool(task) != nil {
+			// 协程池已停止，改为在独立协程中执行，保证邮箱被排空
+			go task()
+		}
+	case d.limit <= 0 || d.running < d.limit:
+		d.running++
+		go d.work(task)
+	default:
+		d.queue.PushBack(task)
+	}
+}
+
+// submitPool 在持有锁的情况下将任务提交到协程池
+// 协程池已停止时任务未被提交，返回 workerpool.ErrStopped
+func (d *dispatcher) submitPool(task func()) error {
+	d.pending++
+	ok := d.pool.TrySubmit(func() {
+		d.mu.Lock()
+		d.pending--
+		d.space.Signal()
+		d.mu.Unlock()
+		task()
+	})
+	if !ok {
+		d.pending--
+		return workerpool.ErrStopped
+	}
+	return nil
+}
+
+// work 是工作协程的主循环：执行任务后继续从队列取出任务，队列为空或超出并发上限时退出
+func (d *dispatcher) work(task func()) {
+	for {
+		task()
+
+		d.mu.Lock()
+		if d.queue.Size() == 0 || (d.limit > 0 && d.running > d.limit) {
+			d.running--
+			d.mu.Unlock()
+			return
+		}
+		task = d.queue.PopFront()
+		d.space.Signal()
+		d.mu.Unlock()
+	}
+}
+
+// configure 在持有锁的情况下修改配置，并根据新配置启动工作协程、唤醒阻塞的提交方
+func (d *dispatcher) configure(fn func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fn()
+	for d.queue.Size() > 0 && (d.limit <= 0 || d.running < d.limit) {
+		d.running++
+		go d.work(d.queue.PopFront())
+	}
+	d.space.Broadcast()
+}
+
+// SetQueueSize 设置异步分发队列的容量与溢出策略
+// 参数 n: 等待执行的监听器调用数上限，n <= 0 表示无限制（默认）
+// 参数 policy: 队列已满时的处理策略
+// 队列在所有事件间共享，仅在通过 SetConcurrency 或 SetWorkerPool 限制并发时才会积压
+// 注意：使用 OverflowBlock 时，在监听器内部调用 Emit 或 EmitWait 可能因队列与并发数耗尽而死锁
+func (e *Emitter[E, T]) SetQueueSize(n int, policy OverflowPolicy) *Emitter[E, T] {
+	e.dispatch.configure(func() {
+		e.dispatch.queueSize = n
+		e.dispatch.policy = policy
+	})
+	return e
+}
+
+// SetWorkerPool 使用协程池执行异步监听器调用，并发度由协程池决定
+// 参数 pool: 协程池，nil 表示恢复使用 Emitter 自身的工作协程
+// 设置后 SetConcurrency 不再生效，SetQueueSize 限制已提交到协程池但尚未开始执行的调用数
+// 协程池停止后，Emit 与 EmitWait 丢弃监听器调用并返回 workerpool.ErrStopped
+func (e *Emitter[E, T]) SetWorkerPool(pool *workerpool.WorkerPool) *Emitter[E, T] {
+	e.dispatch.configure(func() {
+		e.dispatch.pool = pool
+	})
+	return e
+}
+
+// Dropped 返回因分发队列已满或协程池已停止而被丢弃的监听器调用次数
+func (e *Emitter[E, T]) Dropped() uint64 {
+	return e.dispatch.dropped.Load()
+}
@@ -0,0 +1,214 @@
+package emission
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wsshow/op/workerpool"
+)
+
+// concurrencyProbe 记录监听器的最大并发执行数
+type concurrencyProbe struct {
+	current atomic.Int32
+	max     atomic.Int32
+	calls   atomic.Int32
+}
+
+// listener 返回一个执行 d 时长并记录并发数的监听器
+func (p *concurrencyProbe) listener(d time.Duration) Listener[int] {
+	return func(args ...int) {
+		n := p.current.Add(1)
+		for {
+			m := p.max.Load()
+			if n <= m || p.max.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(d)
+		p.current.Add(-1)
+		p.calls.Add(1)
+	}
+}
+
+// TestGlobalConcurrencyAcrossEmits 测试并发度限制在多次 Emit 与多个事件间共享
+func TestGlobalConcurrencyAcrossEmits(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetConcurrency(2)
+	var probe concurrencyProbe
+	for range 4 {
+		em.On("a", probe.listener(10*time.Millisecond))
+		em.On("b", probe.listener(10*time.Millisecond))
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			em.EmitWait("a")
+		}()
+		go func() {
+			defer wg.Done()
+			em.Emit("b")
+		}()
+	}
+	wg.Wait()
+
+	waitUntil(t, func() bool { return probe.calls.Load() == 40 })
+	if m := probe.max.Load(); m > 2 {
+		t.Errorf("Max concurrent should be <= 2 across all emits, got %d", m)
+	}
+}
+
+// TestOverflowDrop 测试队列已满时丢弃监听器调用
+func TestOverflowDrop(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetConcurrency(1).SetQueueSize(2, OverflowDrop)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	em.On("evt", func(args ...int) {
+		<-release
+		calls.Add(1)
+	})
+
+	for range 5 {
+		if err := em.Emit("evt"); err != nil {
+			t.Errorf("OverflowDrop should not return error, got %v", err)
+		}
+	}
+	if d := em.Dropped(); d != 2 {
+		t.Errorf("Expected 2 dropped calls, got %d", d)
+	}
+
+	close(release)
+	waitUntil(t, func() bool { return calls.Load() == 3 })
+}
+
+// TestOverflowError 测试队列已满时返回 ErrQueueFull
+func TestOverflowError(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetConcurrency(1).SetQueueSize(1, OverflowError)
+
+	release := make(chan struct{})
+	em.On("evt", func(args ...int) { <-release })
+
+	if err := em.Emit("evt"); err != nil {
+		t.Errorf("First emit should run immediately, got %v", err)
+	}
+	if err := em.Emit("evt"); err != nil {
+		t.Errorf("Second emit should be queued, got %v", err)
+	}
+	if err := em.Emit("evt"); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+	if d := em.Dropped(); d != 1 {
+		t.Errorf("Expected 1 dropped call, got %d", d)
+	}
+	close(release)
+}
+
+// TestOverflowBlock 测试队列已满时阻塞直到出现空位
+func TestOverflowBlock(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetConcurrency(1).SetQueueSize(1, OverflowBlock)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	em.On("evt", func(args ...int) {
+		<-release
+		calls.Add(1)
+	})
+
+	em.Emit("evt")
+	em.Emit("evt")
+	returned := make(chan struct{})
+	go func() {
+		em.Emit("evt")
+		close(returned)
+	}()
+
+	select {
+	case <-returned:
+		t.Fatal("Emit should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("Emit should return once the queue has space")
+	}
+	waitUntil(t, func() bool { return calls.Load() == 3 })
+	if d := em.Dropped(); d != 0 {
+		t.Errorf("OverflowBlock should not drop calls, got %d", d)
+	}
+}
+
+// TestSetWorkerPool 测试使用协程池执行异步监听器
+func TestSetWorkerPool(t *testing.T) {
+	pool := workerpool.New(3)
+	em := NewEmitter[string, int]()
+	em.SetWorkerPool(pool)
+
+	var probe concurrencyProbe
+	for range 10 {
+		em.On("evt", probe.listener(10*time.Millisecond))
+	}
+	em.EmitWait("evt")
+	em.EmitWait("evt")
+
+	if c := probe.calls.Load(); c != 20 {
+		t.Errorf("Expected 20 calls, got %d", c)
+	}
+	if m := probe.max.Load(); m > 3 {
+		t.Errorf("Max concurrent should be bounded by pool size 3, got %d", m)
+	}
+
+	pool.Stop()
+	if err := em.Emit("evt"); !errors.Is(err, workerpool.ErrStopped) {
+		t.Errorf("Expected ErrStopped after pool stop, got %v", err)
+	}
+	if d := em.Dropped(); d != 10 {
+		t.Errorf("Expected 10 dropped calls, got %d", d)
+	}
+}
+
+// TestRaiseConcurrencyDrainsQueue 测试提高并发度后立即执行排队的监听器调用
+func TestRaiseConcurrencyDrainsQueue(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetConcurrency(1)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	em.On("block", func(args ...int) { <-release })
+	em.On("evt", func(args ...int) { calls.Add(1) })
+
+	em.Emit("block")
+	em.Emit("evt")
+	em.Emit("evt")
+	time.Sleep(20 * time.Millisecond)
+	if calls.Load() != 0 {
+		t.Fatalf("Queued calls should wait for the busy worker, got %d", calls.Load())
+	}
+
+	em.SetConcurrency(3)
+	waitUntil(t, func() bool { return calls.Load() == 2 })
+	close(release)
+}
+
+// waitUntil 等待 cond 成立，超时则失败
+func waitUntil(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timeout waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -3,7 +3,11 @@
 package emission
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 )
 
 // DefaultMaxListeners 默认的最大监听器数量
@@ -22,23 +26,50 @@ type RecoveryListener[E comparable, T any] func(event E, listener any, panicValu
 // Listener 定义监听器函数的签名，接受泛型参数
 type Listener[T any] func(args ...T)
 
+// AnyListener 定义 OnAny 监听器的签名，额外接收实际触发的事件标识
+type AnyListener[E comparable, T any] func(event E, args ...T)
+
 // listenerWrapper 包装监听器并添加唯一标识
-type listenerWrapper[T any] struct {
-	id       uint64      // 唯一标识符
-	listener Listener[T] // 实际的监听器函数
-	isOnce   bool        // 是否为 Once 监听器
+type listenerWrapper[E comparable, T any] struct {
+	id       uint64        // 唯一标识符
+	listener Listener[T]   // 实际的监听器函数
+	handler  Handler[E, T] // 事件处理器，非 nil 时代替 listener 执行
+	isOnce   bool          // 是否为 Once 监听器
+	priority int           // 优先级，数值越大越先执行
+
+	boxOnce sync.Once                  // 保证邮箱只创建一次
+	box     *mailbox                   // 邮箱模式下按触发顺序排队的投递
+	gate    atomic.Pointer[replayGate] // 粘性回放期间暂存实时触发，nil 表示不在回放中
+}
+
+// fn 返回实际的监听器函数或事件处理器，用于回调恢复监听器与慢监听器处理函数
+func (w *listenerWrapper[E, T]) fn() any {
+	if w.handler != nil {
+		return w.handler
+	}
+	return w.listener
 }
 
 // Emitter 是一个泛型事件发射器，用于管理事件的监听和触发
 // E: 事件标识类型（必须是 comparable），T: 监听器参数类型（可以是任意类型）
 type Emitter[E comparable, T any] struct {
-	mu           sync.Mutex                  // 互斥锁，确保线程安全
-	events       map[E][]*listenerWrapper[T] // 事件到监听器列表的映射
-	recoverer    RecoveryListener[E, T]      // 可选的恢复监听器，用于处理 panic
-	maxListeners int                         // 每个事件的最大监听器数量，用于调试内存泄漏
-	nextID       uint64                      // 下一个监听器的ID
-	logger       Logger                      // 可选的日志记录器
-	semaphore    chan struct{}               // 并发度限制信号量，nil 表示无限制
+	mu           sync.Mutex                          // 互斥锁，确保线程安全
+	events       map[E][]*listenerWrapper[E, T]      // 事件到监听器列表的映射
+	recoverer    RecoveryListener[E, T]              // 可选的恢复监听器，用于处理 panic
+	maxListeners int                                 // 每个事件的最大监听器数量，用于调试内存泄漏
+	nextID       uint64                              // 下一个监听器的ID
+	logger       Logger                              // 可选的日志记录器
+	dispatch     *dispatcher                         // 所有事件共享的异步分发队列
+	patterns     matcher[E, T]                       // 可选的通配符匹配器，nil 表示只按事件标识精确匹配
+	anyListeners []*listenerWrapper[E, T]            // 监听所有事件的监听器
+	mailboxes    atomic.Pointer[mailboxConfig[E]]    // 邮箱模式配置，nil 表示未启用
+	subDropped   atomic.Uint64                       // 因订阅通道已满而丢弃的事件数
+	sticky       map[E]*stickyEvents[T]              // 启用粘性触发的事件及其最近的触发参数
+	clock        Clock                               // 防抖、节流与合并使用的时间源
+	limits       atomic.Pointer[map[E]limiter[E, T]] // 事件的防抖、节流或合并策略，nil 表示未设置
+
+	emitMiddleware     atomic.Pointer[[]EmitMiddleware[E, T]]     // emit 中间件链，按注册顺序由外到内
+	listenerMiddleware atomic.Pointer[[]ListenerMiddleware[E, T]] // 监听器中间件链，按注册顺序由外到内
 }
 
 // NewEmitter 创建一个新的泛型事件发射器
@@ -46,34 +77,70 @@ type Emitter[E comparable, T any] struct {
 // 返回初始化好的 Emitter 实例，默认最大监听器数为 DefaultMaxListeners
 func NewEmitter[E comparable, T any]() *Emitter[E, T] {
 	return &Emitter[E, T]{
-		events:       make(map[E][]*listenerWrapper[T]),
+		events:       make(map[E][]*listenerWrapper[E, T]),
 		maxListeners: DefaultMaxListeners,
 		nextID:       1,
+		dispatch:     newDispatcher(),
+		clock:        realClock{},
 	}
 }
 
 // addListener 内部方法，添加监听器到指定事件
 // 参数 once: 是否为一次性监听器
+// 参数 priority: 监听器优先级
 // 返回一个取消函数，调用该函数可移除此监听器
-func (e *Emitter[E, T]) addListener(event E, listener Listener[T], once bool) func() {
+func (e *Emitter[E, T]) addListener(event E, listener Listener[T], once bool, priority int) func() {
+	return e.add(event, &listenerWrapper[E, T]{listener: listener, isOnce: once, priority: priority})
+}
+
+// add 内部方法，为 wrapper 分配 ID 并插入到指定事件的监听器列表
+// 监听器列表按优先级从高到低排列，同优先级的监听器保持注册顺序
+// 若事件记录了粘性触发，注册后在调用方协程中依次回放
+// 返回一个取消函数，调用该函数可移除此监听器
+func (e *Emitter[E, T]) add(event E, wrapper *listenerWrapper[E, T]) func() {
 	e.mu.Lock()
 
-	if e.maxListeners != -1 && len(e.events[event])+1 > e.maxListeners {
+	pattern := e.patterns != nil && e.patterns.accepts(event)
+	var replay [][]T
+	if !pattern {
+		replay = e.stickyArgs(event)
+	}
+	recoverer := e.recoverer
+	if wrapper.isOnce && len(replay) > 0 {
+		// 一次性监听器直接以最近一次粘性触发执行，不再注册
+		e.mu.Unlock()
+		e.replay(event, wrapper, replay[len(replay)-1:], recoverer)
+		return func() {}
+	}
+
+	if e.maxListeners != -1 && e.listenerCount(event)+1 > e.maxListeners {
 		if e.logger != nil {
 			e.logger.Warnf("event `%v` exceeds max listeners limit of %d", event, e.maxListeners)
 		}
 	}
 
+	if len(replay) > 0 {
+		// 监听器在回放结束前即可见，回放期间到达的实时触发暂存到回放结束后投递
+		wrapper.gate.Store(&replayGate{})
+	}
 	id := e.nextID
 	e.nextID++
-	wrapper := &listenerWrapper[T]{
-		id:       id,
-		listener: listener,
-		isOnce:   once,
+	wrapper.id = id
+	if pattern {
+		e.patterns.add(event, wrapper)
+	} else {
+		e.events[event] = insertListener(e.events[event], wrapper)
 	}
-	e.events[event] = append(e.events[event], wrapper)
 	e.mu.Unlock()
 
+	if len(replay) > 0 {
+		func() {
+			// 回放中的 panic 未被恢复时同样投递暂存的触发，避免监听器此后再也收不到事件
+			defer e.releaseGate(wrapper)
+			e.replay(event, wrapper, replay, recoverer)
+		}()
+	}
+
 	// 返回取消函数
 	return func() {
 		e.removeListenerByID(event, id)
@@ -86,13 +153,23 @@ func (e *Emitter[E, T]) addListener(event E, listener Listener[T], once bool) fu
 // 返回一个取消函数，调用该函数可移除此监听器
 // 如果监听器数量超过 maxListeners，会通过 logger 记录警告
 func (e *Emitter[E, T]) AddListener(event E, listener Listener[T]) func() {
-	return e.addListener(event, listener, false)
+	return e.addListener(event, listener, false, 0)
+}
+
+// AddListenerWithPriority 添加带优先级的监听器到指定事件
+// 参数 event: 事件标识
+// 参数 priority: 优先级，数值越大越先执行，AddListener 添加的监听器优先级为 0
+// 参数 listener: 监听器函数
+// 返回一个取消函数，调用该函数可移除此监听器
+// 同优先级的监听器按注册顺序执行，移除其他监听器不会改变剩余监听器的顺序
+func (e *Emitter[E, T]) AddListenerWithPriority(event E, priority int, listener Listener[T]) func() {
+	return e.addListener(event, listener, false, priority)
 }
 
 // On 是 AddListener 的别名
 // 返回一个取消函数，调用该函数可移除此监听器
 func (e *Emitter[E, T]) On(event E, listener Listener[T]) func() {
-	return e.addListener(event, listener, false)
+	return e.addListener(event, listener, false, 0)
 }
 
 // Once 添加一个只触发一次的监听器
@@ -101,65 +178,112 @@ func (e *Emitter[E, T]) On(event E, listener Listener[T]) func() {
 // 返回一个取消函数，调用该函数可在触发前移除此监听器
 // 触发后自动移除
 func (e *Emitter[E, T]) Once(event E, listener Listener[T]) func() {
-	return e.addListener(event, listener, true)
+	return e.addListener(event, listener, true, 0)
+}
+
+// OnceWithPriority 添加一个只触发一次的带优先级监听器
+// 参数 event: 事件标识
+// 参数 priority: 优先级，数值越大越先执行
+// 参数 listener: 监听器函数
+// 返回一个取消函数，调用该函数可在触发前移除此监听器
+func (e *Emitter[E, T]) OnceWithPriority(event E, priority int, listener Listener[T]) func() {
+	return e.addListener(event, listener, true, priority)
+}
+
+// OnAny 添加监听所有事件的监听器
+// 参数 listener: 监听器函数，第一个参数为实际触发的事件标识
+// 返回一个取消函数，调用该函数可移除此监听器
+// 与其他监听器一起按优先级（为 0）与注册顺序执行，不计入 GetListenerCount
+func (e *Emitter[E, T]) OnAny(listener AnyListener[E, T]) func() {
+	wrapper := &listenerWrapper[E, T]{
+		handler: func(ev *Event[E, T]) (any, error) {
+			listener(ev.Name, ev.Args...)
+			return nil, nil
+		},
+	}
+
+	e.mu.Lock()
+	id := e.nextID
+	e.nextID++
+	wrapper.id = id
+	e.anyListeners = insertListener(e.anyListeners, wrapper)
+	e.mu.Unlock()
+
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.anyListeners = deleteListener(e.anyListeners, id)
+	}
 }
 
 // removeListenerByID 通过 ID 移除监听器（内部方法）
-// 使用 swap-remove 技术优化性能，时间复杂度 O(1)
-// 注意：swap-remove 不保留监听器的注册顺序
+// 删除时保持剩余监听器的相对顺序，从而保证优先级与注册顺序不被打乱
 func (e *Emitter[E, T]) removeListenerByID(event E, id uint64) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.patterns != nil && e.patterns.accepts(event) {
+		e.patterns.remove(event, id)
+		return
+	}
+
 	listeners, ok := e.events[event]
 	if !ok {
 		return
 	}
-
-	// 查找并使用 swap-remove 技术删除（交换最后一个元素，然后截断）
-	for i, wrapper := range listeners {
-		if wrapper.id == id {
-			lastIdx := len(listeners) - 1
-			listeners[i] = listeners[lastIdx]
-			listeners[lastIdx] = nil // 避免内存泄漏
-			e.events[event] = listeners[:lastIdx]
-
-			if lastIdx == 0 {
-				delete(e.events, event)
-			}
-			return
-		}
+	if listeners = deleteListener(listeners, id); len(listeners) == 0 {
+		delete(e.events, event)
+	} else {
+		e.events[event] = listeners
 	}
 }
 
 // RemoveAllListeners 移除指定事件的所有监听器
-// 参数 event: 事件标识
+// 参数 event: 事件标识，对于通配符模式只移除该模式下的监听器
 func (e *Emitter[E, T]) RemoveAllListeners(event E) *Emitter[E, T] {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	delete(e.events, event)
+	if e.patterns != nil && e.patterns.accepts(event) {
+		e.patterns.removeAll(event)
+	} else {
+		delete(e.events, event)
+	}
 	return e
 }
 
-// prepareEmit 原子地复制监听器列表并移除 once 监听器
-// 在持有锁的情况下完成快照操作，避免 once 监听器在并发 Emit 中被重复触发
-// 返回要执行的监听器副本、信号量快照和恢复监听器快照，若无监听器则返回 nil
-func (e *Emitter[E, T]) prepareEmit(event E) ([]*listenerWrapper[T], chan struct{}, RecoveryListener[E, T]) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
+// listenerCount 在持有锁的情况下返回指定事件或通配符模式下的监听器数量
+func (e *Emitter[E, T]) listenerCount(event E) int {
+	if e.patterns != nil && e.patterns.accepts(event) {
+		return e.patterns.count(event)
+	}
+	return len(e.events[event])
+}
 
-	listeners, ok := e.events[event]
-	if !ok || len(listeners) == 0 {
-		return nil, nil, nil
+// insertListener 将 wrapper 插入到最后一个优先级不低于它的监听器之后
+// 保证列表按优先级从高到低排列，同优先级按注册顺序排列
+func insertListener[E comparable, T any](listeners []*listenerWrapper[E, T], wrapper *listenerWrapper[E, T]) []*listenerWrapper[E, T] {
+	i := len(listeners)
+	for i > 0 && listeners[i-1].priority < wrapper.priority {
+		i--
 	}
+	return slices.Insert(listeners, i, wrapper)
+}
 
-	// 快照信号量和恢复监听器引用，保证后续使用无数据竞争
-	sem := e.semaphore
-	recoverer := e.recoverer
+// deleteListener 从列表中删除指定 ID 的监听器，保持剩余监听器的相对顺序
+func deleteListener[E comparable, T any](listeners []*listenerWrapper[E, T], id uint64) []*listenerWrapper[E, T] {
+	for i, wrapper := range listeners {
+		if wrapper.id == id {
+			// slices.Delete 会清零被截断的元素，避免内存泄漏
+			return slices.Delete(listeners, i, i+1)
+		}
+	}
+	return listeners
+}
 
-	// 复制监听器列表供调用方使用
-	result := make([]*listenerWrapper[T], len(listeners))
-	copy(result, listeners)
+// takeListeners 将 listeners 追加到 dst，并就地移除其中的 once 监听器
+// 返回追加后的 dst 与剩余的监听器列表
+func takeListeners[E comparable, T any](dst, listeners []*listenerWrapper[E, T]) ([]*listenerWrapper[E, T], []*listenerWrapper[E, T]) {
+	dst = append(dst, listeners...)
 
 	// 检查是否存在 once 监听器
 	hasOnce := false
@@ -170,7 +294,7 @@ func (e *Emitter[E, T]) prepareEmit(event E) ([]*listenerWrapper[T], chan struct
 		}
 	}
 	if !hasOnce {
-		return result, sem, recoverer
+		return dst, listeners
 	}
 
 	// 就地过滤，从源列表中移除 once 监听器
@@ -182,128 +306,183 @@ func (e *Emitter[E, T]) prepareEmit(event E) ([]*listenerWrapper[T], chan struct
 		}
 	}
 	// 清除剩余引用，避免内存泄漏
-	for i := n; i < len(listeners); i++ {
-		listeners[i] = nil
+	clear(listeners[n:])
+	return dst, listeners[:n]
+}
+
+// sortListeners 将来自多个列表的监听器按优先级从高到低、同优先级按注册顺序排序
+// 同一监听器被多个通配符路径匹配时只保留一次
+func sortListeners[E comparable, T any](listeners []*listenerWrapper[E, T]) []*listenerWrapper[E, T] {
+	slices.SortFunc(listeners, func(a, b *listenerWrapper[E, T]) int {
+		if a.priority != b.priority {
+			return cmp.Compare(b.priority, a.priority)
+		}
+		return cmp.Compare(a.id, b.id)
+	})
+	return slices.CompactFunc(listeners, func(a, b *listenerWrapper[E, T]) bool {
+		return a.id == b.id
+	})
+}
+
+// prepareEmit 原子地复制精确匹配、通配符匹配与全局监听器列表并移除 once 监听器
+// 在持有锁的情况下完成快照操作，避免 once 监听器在并发 Emit 中被重复触发
+// 若事件启用了粘性触发，同时记录本次触发的参数
+// 返回要执行的监听器副本和恢复监听器快照，若无监听器则返回 nil
+func (e *Emitter[E, T]) prepareEmit(ev *Event[E, T]) ([]*listenerWrapper[E, T], RecoveryListener[E, T]) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	event := ev.Name
+	if len(e.sticky) > 0 {
+		e.record(event, ev.Args)
 	}
-	if n == 0 {
-		delete(e.events, event)
-	} else {
-		e.events[event] = listeners[:n]
+
+	var result []*listenerWrapper[E, T]
+	if listeners := e.events[event]; len(listeners) > 0 {
+		result, listeners = takeListeners(result, listeners)
+		if len(listeners) == 0 {
+			delete(e.events, event)
+		} else {
+			e.events[event] = listeners
+		}
+	}
+	exact := len(result)
+	if e.patterns != nil {
+		result = e.patterns.match(event, result)
+	}
+	if len(e.anyListeners) > 0 {
+		result, e.anyListeners = takeListeners(result, e.anyListeners)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	// 合并通配符与全局监听器后重新排序
+	if len(result) > exact {
+		result = sortListeners(result)
 	}
 
-	return result, sem, recoverer
+	// 快照恢复监听器引用，保证后续使用无数据竞争
+	return result, e.recoverer
 }
 
 // Emit 异步触发事件的所有监听器（Fire-and-forget）
 // 参数 event: 事件标识
 // 参数 args: 传递给监听器的参数
-// 注意：此方法立即返回，不等待监听器执行完成
-// 若通过 SetConcurrency 设置了并发度，则使用 worker pool 限制 goroutine 创建数量
-func (e *Emitter[E, T]) Emit(event E, args ...T) {
-	listeners, sem, recoverer := e.prepareEmit(event)
+// 注意：此方法不等待监听器执行完成，每个监听器调用作为一个任务进入所有事件共享的分发队列
+// 并发度由 SetConcurrency 或 SetWorkerPool 全局限制，队列已满时按 SetQueueSize 设置的策略处理
+// 启用邮箱模式（EnableMailbox）时，每个监听器按触发顺序依次接收事件
+// 溢出策略为 OverflowError 时返回 ErrQueueFull，其余情况返回 nil；emit 中间件返回的错误同样由此返回
+func (e *Emitter[E, T]) Emit(event E, args ...T) error {
+	return e.intercept(newEvent(event, args), e.emitAsync)
+}
+
+// emitAsync 是 Emit 在中间件链末端的实现
+func (e *Emitter[E, T]) emitAsync(ev *Event[E, T]) error {
+	listeners, recoverer := e.prepareEmit(ev)
 	if len(listeners) == 0 {
-		return
+		return nil
 	}
 
-	go func() {
-		if sem == nil {
-			// 无并发限制：直接为每个监听器创建 goroutine
-			var wg sync.WaitGroup
-			wg.Add(len(listeners))
-			for _, wrapper := range listeners {
-				go func() {
-					defer wg.Done()
-					e.callListener(event, wrapper.listener, recoverer, args...)
-				}()
-			}
-			wg.Wait()
-		} else {
-			// 有并发限制：使用 worker pool 模式
-			e.runWithWorkerPool(event, listeners, sem, recoverer, args...)
+	cfg := e.mailboxes.Load()
+	var firstErr error
+	for _, wrapper := range listeners {
+		err := e.schedule(cfg, wrapper, ev, func() {
+			e.callListener(ev, wrapper, recoverer)
+		})
+		if err != nil && firstErr == nil {
+			firstErr = err
 		}
-	}()
+	}
+	return firstErr
 }
 
 // EmitWait 并发触发事件的所有监听器，并等待所有监听器执行完成
 // 参数 event: 事件标识
 // 参数 args: 传递给监听器的参数
-// 注意：此方法会阻塞直到所有监听器执行完成
-// 若通过 SetConcurrency 设置了并发度，则使用 worker pool 限制 goroutine 创建数量
-func (e *Emitter[E, T]) EmitWait(event E, args ...T) {
-	listeners, sem, recoverer := e.prepareEmit(event)
+// 注意：此方法会阻塞直到所有已入队的监听器执行完成，并发度与溢出策略同 Emit
+// 在监听器内部调用 EmitWait 时，若全局并发数已被占满将发生死锁
+func (e *Emitter[E, T]) EmitWait(event E, args ...T) error {
+	return e.intercept(newEvent(event, args), e.emitWait)
+}
+
+// emitWait 是 EmitWait 在中间件链末端的实现
+func (e *Emitter[E, T]) emitWait(ev *Event[E, T]) error {
+	listeners, recoverer := e.prepareEmit(ev)
 	if len(listeners) == 0 {
-		return
+		return nil
 	}
 
-	if sem == nil {
-		// 无并发限制：直接为每个监听器创建 goroutine
-		var wg sync.WaitGroup
-		wg.Add(len(listeners))
-		for _, wrapper := range listeners {
-			go func() {
-				defer wg.Done()
-				e.callListener(event, wrapper.listener, recoverer, args...)
-			}()
+	cfg := e.mailboxes.Load()
+	var wg sync.WaitGroup
+	var firstErr error
+	for _, wrapper := range listeners {
+		wg.Add(1)
+		err := e.schedule(cfg, wrapper, ev, func() {
+			defer wg.Done()
+			e.callListener(ev, wrapper, recoverer)
+		})
+		if err != nil {
+			wg.Done()
+			if firstErr == nil {
+				firstErr = err
+			}
 		}
-		wg.Wait()
-	} else {
-		// 有并发限制：使用 worker pool 模式
-		e.runWithWorkerPool(event, listeners, sem, recoverer, args...)
 	}
+	wg.Wait()
+	return firstErr
 }
 
 // EmitSync 同步触发事件的所有监听器
 // 参数 event: 事件标识
 // 参数 args: 传递给监听器的参数
-// 注意：此方法按顺序同步执行所有监听器，不受 SetConcurrency 影响
+// 注意：此方法按优先级从高到低、同优先级按注册顺序同步执行所有监听器，不受 SetConcurrency 影响
+// 事件处理器调用 StopPropagation 后，后续监听器不再执行
 func (e *Emitter[E, T]) EmitSync(event E, args ...T) {
-	listeners, _, recoverer := e.prepareEmit(event)
-	if len(listeners) == 0 {
-		return
-	}
-
-	for _, wrapper := range listeners {
-		e.callListener(event, wrapper.listener, recoverer, args...)
-	}
+	e.intercept(newEvent(event, args), func(ev *Event[E, T]) error {
+		listeners, recoverer := e.prepareEmit(ev)
+		for _, wrapper := range listeners {
+			e.callListener(ev, wrapper, recoverer)
+			if ev.IsPropagationStopped() {
+				break
+			}
+		}
+		return nil
+	})
 }
 
-// runWithWorkerPool 使用 worker pool 模式执行监听器
-// 只创建 min(cap(sem), len(listeners)) 个 worker goroutine，避免创建多余的空闲 goroutine
-func (e *Emitter[E, T]) runWithWorkerPool(event E, listeners []*listenerWrapper[T], sem chan struct{}, recoverer RecoveryListener[E, T], args ...T) {
-	workerCount := min(cap(sem), len(listeners))
-	tasks := make(chan *listenerWrapper[T], len(listeners))
-
-	// 填充任务队列
-	for _, wrapper := range listeners {
-		tasks <- wrapper
-	}
-	close(tasks)
-
-	// 启动固定数量的 worker goroutine
-	var wg sync.WaitGroup
-	wg.Add(workerCount)
-	for range workerCount {
-		go func() {
-			defer wg.Done()
-			for wrapper := range tasks {
-				e.callListener(event, wrapper.listener, recoverer, args...)
-			}
-		}()
+// callListener 调用监听器或事件处理器并处理可能的 panic
+// recoverer 必须是在持有锁期间快照的值，避免数据竞争
+// 调用经过监听器中间件链，中间件中的 panic 同样被恢复
+// 返回事件处理器的返回值；普通监听器返回 nil, nil；panic 被恢复时返回 ErrListenerPanic
+// 监听器正在回放粘性触发时，本次调用被暂存到回放结束后执行，并立即返回 nil, nil
+func (e *Emitter[E, T]) callListener(ev *Event[E, T], wrapper *listenerWrapper[E, T], recoverer RecoveryListener[E, T]) (result any, err error) {
+	if gate := wrapper.gate.Load(); gate != nil && gate.hold(func() { e.invokeListener(ev, wrapper, recoverer) }) {
+		return nil, nil
 	}
-	wg.Wait()
+	return e.invokeListener(ev, wrapper, recoverer)
 }
 
-// callListener 调用监听器并处理可能的 panic
-// recoverer 必须是在持有锁期间快照的值，避免数据竞争
-func (e *Emitter[E, T]) callListener(event E, listener Listener[T], recoverer RecoveryListener[E, T], args ...T) {
+// invokeListener 调用监听器或事件处理器，不经过粘性回放的暂存
+func (e *Emitter[E, T]) invokeListener(ev *Event[E, T], wrapper *listenerWrapper[E, T], recoverer RecoveryListener[E, T]) (result any, err error) {
 	if recoverer != nil {
 		defer func() {
 			if r := recover(); r != nil {
-				recoverer(event, listener, r)
+				recoverer(ev.Name, wrapper.fn(), r)
+				result, err = nil, fmt.Errorf("%w: %v", ErrListenerPanic, r)
 			}
 		}()
 	}
-	listener(args...)
+	invoke := func() (any, error) {
+		if wrapper.handler != nil {
+			return wrapper.handler(ev)
+		}
+		wrapper.listener(ev.Args...)
+		return nil, nil
+	}
+	if mws := e.listenerMiddleware.Load(); mws != nil {
+		invoke = chainListener(*mws, ev, wrapper.fn(), invoke)
+	}
+	return invoke()
 }
 
 // RecoverWith 设置恢复监听器，用于处理 panic
@@ -327,15 +506,11 @@ func (e *Emitter[E, T]) SetLogger(logger Logger) *Emitter[E, T] {
 // SetConcurrency 设置并发执行监听器的最大数量
 // 参数 n: 最大并发数，n <= 0 表示无限制（默认）
 // 影响 Emit 和 EmitWait，不影响 EmitSync
-// 信号量在所有事件间共享，用于全局 goroutine 背压控制
+// 限制在所有事件与所有 Emit 调用间共享，超出的监听器调用在分发队列中等待，最多创建 n 个工作协程
 func (e *Emitter[E, T]) SetConcurrency(n int) *Emitter[E, T] {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	if n <= 0 {
-		e.semaphore = nil
-	} else {
-		e.semaphore = make(chan struct{}, n)
-	}
+	e.dispatch.configure(func() {
+		e.dispatch.limit = n
+	})
 	return e
 }
 
@@ -349,9 +524,10 @@ func (e *Emitter[E, T]) SetMaxListeners(max int) *Emitter[E, T] {
 }
 
 // GetListenerCount 获取指定事件的监听器数量
-// 参数 event: 事件标识
+// 参数 event: 事件标识，对于通配符模式返回该模式下的监听器数量
+// 不包括匹配该事件的通配符监听器与 OnAny 监听器
 func (e *Emitter[E, T]) GetListenerCount(event E) int {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	return len(e.events[event])
+	return e.listenerCount(event)
 }
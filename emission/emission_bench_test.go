@@ -140,3 +140,18 @@ func BenchmarkHighFrequencyEmitWithConcurrency(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkTopicEmitSync 测试大量通配符模式下主题匹配的性能
+func BenchmarkTopicEmitSync(b *testing.B) {
+	em := NewTopicEmitter[string]()
+	em.SetMaxListeners(-1)
+	for i := 0; i < 1000; i++ {
+		em.On(fmt.Sprintf("service%d.*.created", i), func(args ...string) {})
+	}
+	em.On("service1.#", func(args ...string) {})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		em.EmitSync("service1.order.created", "data")
+	}
+}
@@ -1,6 +1,7 @@
 package emission
 
 import (
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -615,3 +616,72 @@ func TestWorkerPoolActuallyLimitsGoroutines(t *testing.T) {
 		t.Errorf("With SetConcurrency(3) and worker pool, maxActive should be <= 3, got %d", maxActive)
 	}
 }
+
+// TestListenerPriority 测试监听器按优先级从高到低执行，同优先级保持注册顺序
+func TestListenerPriority(t *testing.T) {
+	em := NewEmitter[string, int]()
+	var order []string
+	record := func(name string) Listener[int] {
+		return func(args ...int) { order = append(order, name) }
+	}
+
+	em.On("req", record("handler1"))
+	em.AddListenerWithPriority("req", 10, record("audit"))
+	em.AddListenerWithPriority("req", 100, record("auth"))
+	em.On("req", record("handler2"))
+	em.AddListenerWithPriority("req", 10, record("metrics"))
+	em.AddListenerWithPriority("req", -1, record("cleanup"))
+
+	em.EmitSync("req", 1)
+
+	expected := []string{"auth", "audit", "metrics", "handler1", "handler2", "cleanup"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("Expected order %v, got %v", expected, order)
+	}
+}
+
+// TestListenerOrderAfterRemoval 测试移除监听器后剩余监听器的顺序不变
+func TestListenerOrderAfterRemoval(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetMaxListeners(-1)
+	var order []int
+	var cancels []func()
+	for i := range 6 {
+		cancels = append(cancels, em.On("evt", func(args ...int) { order = append(order, i) }))
+	}
+
+	cancels[0]()
+	cancels[3]()
+	em.EmitSync("evt")
+	if expected := []int{1, 2, 4, 5}; !slices.Equal(order, expected) {
+		t.Errorf("Expected order %v after removal, got %v", expected, order)
+	}
+
+	// 移除后新增的监听器排在同优先级监听器之后
+	order = nil
+	em.On("evt", func(args ...int) { order = append(order, 6) })
+	em.EmitSync("evt")
+	if expected := []int{1, 2, 4, 5, 6}; !slices.Equal(order, expected) {
+		t.Errorf("Expected order %v after re-adding, got %v", expected, order)
+	}
+}
+
+// TestOnceWithPriority 测试带优先级的一次性监听器
+func TestOnceWithPriority(t *testing.T) {
+	em := NewEmitter[string, int]()
+	var order []string
+	em.On("evt", func(args ...int) { order = append(order, "on") })
+	em.OnceWithPriority("evt", 5, func(args ...int) { order = append(order, "once") })
+	em.AddListenerWithPriority("evt", 1, func(args ...int) { order = append(order, "high") })
+
+	em.EmitSync("evt")
+	em.EmitSync("evt")
+
+	expected := []string{"once", "high", "on", "high", "on"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("Expected order %v, got %v", expected, order)
+	}
+	if count := em.GetListenerCount("evt"); count != 2 {
+		t.Errorf("Once listener should be removed after emit, got %d listeners", count)
+	}
+}
@@ -0,0 +1,121 @@
+package emission
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrListenerPanic 表示监听器发生了 panic 并被恢复监听器捕获
+// 仅在通过 RecoverWith 设置了恢复监听器时出现在 EmitResult.Errors 中
+var ErrListenerPanic = errors.New("emission: listener panicked")
+
+// Event 是传递给事件处理器的事件上下文
+// 处理器可以通过它读取事件标识和参数、阻止事件继续传播或标记默认行为被阻止
+type Event[E comparable, T any] struct {
+	Name E   // 事件标识
+	Args []T // 触发事件时传入的参数
+
+	stopped   atomic.Bool
+	prevented atomic.Bool
+}
+
+// newEvent 创建事件上下文
+func newEvent[E comparable, T any](name E, args []T) *Event[E, T] {
+	return &Event[E, T]{Name: name, Args: args}
+}
+
+// StopPropagation 阻止事件继续传播，EmitSync 与 EmitSyncResult 不再执行后续监听器
+// 注意：Emit 与 EmitWait 并发执行监听器，不受此标记影响
+func (ev *Event[E, T]) StopPropagation() {
+	ev.stopped.Store(true)
+}
+
+// IsPropagationStopped 返回事件是否已被阻止传播
+func (ev *Event[E, T]) IsPropagationStopped() bool {
+	return ev.stopped.Load()
+}
+
+// PreventDefault 标记事件的默认行为被阻止，由触发方通过 EmitResult.DefaultPrevented 决定如何处理
+// 与 StopPropagation 不同，后续监听器仍会执行
+func (ev *Event[E, T]) PreventDefault() {
+	ev.prevented.Store(true)
+}
+
+// IsDefaultPrevented 返回事件的默认行为是否已被阻止
+func (ev *Event[E, T]) IsDefaultPrevented() bool {
+	return ev.prevented.Load()
+}
+
+// Handler 定义事件处理器的签名
+// 处理器接收事件上下文，可以返回一个结果值或错误，由 EmitSyncResult 汇总
+type Handler[E comparable, T any] func(ev *Event[E, T]) (any, error)
+
+// EmitResult 是 EmitSyncResult 的汇总结果
+type EmitResult struct {
+	Results          []any   // 按执行顺序排列的处理器返回值，不包括返回错误或 nil 值的处理器
+	Errors           []error // 按执行顺序排列的处理器错误
+	Stopped          bool    // 事件是否被阻止传播
+	DefaultPrevented bool    // 事件的默认行为是否被阻止
+}
+
+// Err 将所有处理器错误合并为一个错误，没有错误时返回 nil
+func (r EmitResult) Err() error {
+	return errors.Join(r.Errors...)
+}
+
+// AddHandler 添加事件处理器到指定事件
+// 参数 event: 事件标识
+// 参数 handler: 事件处理器
+// 返回一个取消函数，调用该函数可移除此处理器
+// 处理器与普通监听器共享同一列表，按优先级与注册顺序执行
+func (e *Emitter[E, T]) AddHandler(event E, handler Handler[E, T]) func() {
+	return e.AddHandlerWithPriority(event, 0, handler)
+}
+
+// AddHandlerWithPriority 添加带优先级的事件处理器到指定事件
+// 参数 event: 事件标识
+// 参数 priority: 优先级，数值越大越先执行
+// 参数 handler: 事件处理器
+// 返回一个取消函数，调用该函数可移除此处理器
+func (e *Emitter[E, T]) AddHandlerWithPriority(event E, priority int, handler Handler[E, T]) func() {
+	return e.add(event, &listenerWrapper[E, T]{handler: handler, priority: priority})
+}
+
+// OnceHandler 添加一个只触发一次的事件处理器
+// 参数 event: 事件标识
+// 参数 handler: 事件处理器
+// 返回一个取消函数，调用该函数可在触发前移除此处理器
+func (e *Emitter[E, T]) OnceHandler(event E, handler Handler[E, T]) func() {
+	return e.add(event, &listenerWrapper[E, T]{handler: handler, isOnce: true})
+}
+
+// EmitSyncResult 同步触发事件，并汇总事件处理器的返回值与错误
+// 参数 event: 事件标识
+// 参数 args: 传递给监听器的参数
+// 执行顺序与 EmitSync 相同；处理器返回错误不会中断传播，调用 StopPropagation 后不再执行后续监听器
+// 普通监听器照常执行，但不产生返回值；emit 中间件返回的错误同样计入 Errors
+func (e *Emitter[E, T]) EmitSyncResult(event E, args ...T) EmitResult {
+	var res EmitResult
+	ev := newEvent(event, args)
+	err := e.intercept(ev, func(ev *Event[E, T]) error {
+		listeners, recoverer := e.prepareEmit(ev)
+		for _, wrapper := range listeners {
+			v, err := e.callListener(ev, wrapper, recoverer)
+			if err != nil {
+				res.Errors = append(res.Errors, err)
+			} else if v != nil {
+				res.Results = append(res.Results, v)
+			}
+			if ev.IsPropagationStopped() {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		res.Errors = append(res.Errors, err)
+	}
+	res.Stopped = ev.IsPropagationStopped()
+	res.DefaultPrevented = ev.IsDefaultPrevented()
+	return res
+}
@@ -0,0 +1,147 @@
+package emission
+
+import (
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// TestEmitSyncResult 测试汇总事件处理器的返回值与错误
+func TestEmitSyncResult(t *testing.T) {
+	em := NewEmitter[string, int]()
+	errInvalid := errors.New("invalid")
+
+	em.AddHandler("calc", func(ev *Event[string, int]) (any, error) {
+		return ev.Args[0] * 2, nil
+	})
+	em.On("calc", func(args ...int) {})
+	em.AddHandler("calc", func(ev *Event[string, int]) (any, error) {
+		return nil, errInvalid
+	})
+	em.AddHandler("calc", func(ev *Event[string, int]) (any, error) {
+		return ev.Args[0] + 1, nil
+	})
+
+	res := em.EmitSyncResult("calc", 5)
+	if !slices.Equal(res.Results, []any{10, 6}) {
+		t.Errorf("Expected results [10 6], got %v", res.Results)
+	}
+	if len(res.Errors) != 1 || !errors.Is(res.Err(), errInvalid) {
+		t.Errorf("Expected one invalid error, got %v", res.Errors)
+	}
+	if res.Stopped || res.DefaultPrevented {
+		t.Errorf("Event should not be stopped or prevented, got %+v", res)
+	}
+
+	if res := em.EmitSyncResult("missing"); res.Results != nil || res.Err() != nil {
+		t.Errorf("Emitting an event without listeners should return empty result, got %+v", res)
+	}
+}
+
+// TestStopPropagation 测试阻止事件传播后后续监听器不再执行
+func TestStopPropagation(t *testing.T) {
+	em := NewEmitter[string, string]()
+	var order []string
+
+	em.AddHandlerWithPriority("request", 10, func(ev *Event[string, string]) (any, error) {
+		order = append(order, "auth")
+		if ev.Args[0] != "admin" {
+			ev.StopPropagation()
+			return nil, errors.New("forbidden")
+		}
+		return nil, nil
+	})
+	em.On("request", func(args ...string) { order = append(order, "handler") })
+
+	res := em.EmitSyncResult("request", "guest")
+	if !res.Stopped || res.Err() == nil {
+		t.Errorf("Expected stopped event with error, got %+v", res)
+	}
+	if !slices.Equal(order, []string{"auth"}) {
+		t.Errorf("Handler should not run after StopPropagation, got %v", order)
+	}
+
+	order = nil
+	em.EmitSync("request", "guest")
+	if !slices.Equal(order, []string{"auth"}) {
+		t.Errorf("EmitSync should honor StopPropagation, got %v", order)
+	}
+
+	order = nil
+	em.EmitSync("request", "admin")
+	if !slices.Equal(order, []string{"auth", "handler"}) {
+		t.Errorf("Expected all listeners to run, got %v", order)
+	}
+}
+
+// TestPreventDefault 测试标记默认行为被阻止，后续监听器仍会执行
+func TestPreventDefault(t *testing.T) {
+	em := NewEmitter[string, int]()
+	var ran atomic.Int32
+	var observed atomic.Bool
+	em.AddHandler("submit", func(ev *Event[string, int]) (any, error) {
+		ev.PreventDefault()
+		return nil, nil
+	})
+	em.AddHandler("submit", func(ev *Event[string, int]) (any, error) {
+		ran.Add(1)
+		observed.Store(ev.IsDefaultPrevented())
+		return nil, nil
+	})
+
+	res := em.EmitSyncResult("submit")
+	if !res.DefaultPrevented || res.Stopped {
+		t.Errorf("Expected default prevented without stopping, got %+v", res)
+	}
+	if ran.Load() != 1 || !observed.Load() {
+		t.Errorf("Second handler should run once and observe PreventDefault, ran %d", ran.Load())
+	}
+
+	// 并发触发时处理器同样会被执行
+	em.EmitWait("submit")
+	if ran.Load() != 2 {
+		t.Errorf("EmitWait should run handlers, ran %d", ran.Load())
+	}
+}
+
+// TestHandlerPanicRecovery 测试处理器 panic 被恢复后计入错误
+func TestHandlerPanicRecovery(t *testing.T) {
+	em := NewEmitter[string, int]()
+	var recovered any
+	em.RecoverWith(func(event string, listener any, panicValue any) {
+		recovered = panicValue
+	})
+	em.AddHandler("evt", func(ev *Event[string, int]) (any, error) {
+		panic("boom")
+	})
+	em.AddHandler("evt", func(ev *Event[string, int]) (any, error) {
+		return "ok", nil
+	})
+
+	res := em.EmitSyncResult("evt")
+	if recovered != "boom" {
+		t.Errorf("Recoverer should receive panic value, got %v", recovered)
+	}
+	if !errors.Is(res.Err(), ErrListenerPanic) {
+		t.Errorf("Expected ErrListenerPanic, got %v", res.Errors)
+	}
+	if !slices.Equal(res.Results, []any{"ok"}) {
+		t.Errorf("Expected remaining handler result, got %v", res.Results)
+	}
+}
+
+// TestOnceHandler 测试一次性事件处理器
+func TestOnceHandler(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.OnceHandler("evt", func(ev *Event[string, int]) (any, error) {
+		return 1, nil
+	})
+
+	if res := em.EmitSyncResult("evt"); len(res.Results) != 1 {
+		t.Errorf("Once handler should run on first emit, got %v", res.Results)
+	}
+	if res := em.EmitSyncResult("evt"); len(res.Results) != 0 {
+		t.Errorf("Once handler should not run again, got %v", res.Results)
+	}
+}
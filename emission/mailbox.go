@@ -0,0 +1,161 @@
+package emission
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wsshow/op/deque"
+)
+
+// SlowListener 描述一次慢监听器检测的结果
+type SlowListener[E comparable] struct {
+	Event    E             // 触发检测的事件标识
+	Listener any           // 监听器函数或事件处理器
+	Elapsed  time.Duration // 本次调用的耗时，邮箱已满时为 0
+	Pending  int           // 检测时邮箱中等待投递的事件数
+	Full     bool          // 是否因邮箱已满而触发检测
+}
+
+// mailboxConfig 是邮箱模式的配置，创建后不再修改
+type mailboxConfig[E comparable] struct {
+	size      int                   // 每个监听器邮箱的容量，<= 0 表示无限制
+	policy    OverflowPolicy        // 邮箱已满时的处理策略
+	threshold time.Duration         // 慢监听器的耗时阈值，<= 0 表示不按耗时检测
+	onSlow    func(SlowListener[E]) // 慢监听器处理函数
+}
+
+// mailbox 是单个监听器的投递队列，同一时刻最多有一个排空任务在执行，从而保证投递顺序
+type mailbox struct {
+	mu      sync.Mutex
+	space   *sync.Cond // 邮箱出现空位时通知阻塞的投递方
+	queue   *deque.Deque[func()]
+	running bool // 是否已有排空任务在分发队列中
+}
+
+// mailbox 返回监听器的邮箱，首次调用时创建
+func (w *listenerWrapper[E, T]) mailbox() *mailbox {
+	w.boxOnce.Do(func() {
+		w.box = &mailbox{queue: deque.New[func()]()}
+		w.box.space = sync.NewCond(&w.box.mu)
+	})
+	return w.box
+}
+
+// pending 返回邮箱中等待投递的事件数
+func (b *mailbox) pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.queue.Size()
+}
+
+// drain 依次执行邮箱中的投递，邮箱为空时退出
+func (b *mailbox) drain() {
+	for {
+		b.mu.Lock()
+		if b.queue.Size() == 0 {
+			b.running = false
+			b.mu.Unlock()
+			return
+		}
+		task := b.queue.PopFront()
+		b.space.Signal()
+		b.mu.Unlock()
+		task()
+	}
+}
+
+// EnableMailbox 启用邮箱模式：每个监听器拥有独立的邮箱，按触发顺序依次接收事件
+// 参数 size: 每个邮箱中等待投递的事件数上限，size <= 0 表示无限制
+// 参数 policy: 邮箱已满时的处理策略，丢弃的投递计入 Dropped
+// 同一监听器的调用不会重叠，不同监听器仍然并行执行，并发度仍受 SetConcurrency 或 SetWorkerPool 限制
+// 邮箱模式下 SetQueueSize 不再生效；监听器被移除后，邮箱中已有的投递仍会执行
+func (e *Emitter[E, T]) EnableMailbox(size int, policy OverflowPolicy) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	cfg := &mailboxConfig[E]{}
+	if old := e.mailboxes.Load(); old != nil {
+		*cfg = *old
+	}
+	cfg.size = size
+	cfg.policy = policy
+	e.mailboxes.Store(cfg)
+	return e
+}
+
+// DisableMailbox 关闭邮箱模式，之后的 Emit 恢复为每次调用独立分发
+// 邮箱中已有的投递仍会按顺序执行
+func (e *Emitter[E, T]) DisableMailbox() *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.mailboxes.Store(nil)
+	return e
+}
+
+// OnSlowListener 设置邮箱模式下的慢监听器处理函数
+// 参数 threshold: 单次调用耗时超过该值时回调 fn，<= 0 表示只在邮箱已满时回调
+// 参数 fn: 处理函数，在执行监听器或投递事件的协程中调用，不应阻塞
+// 必须在 EnableMailbox 之后调用，否则不生效
+func (e *Emitter[E, T]) OnSlowListener(threshold time.Duration, fn func(info SlowListener[E])) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	old := e.mailboxes.Load()
+	if old == nil {
+		return e
+	}
+	cfg := *old
+	cfg.threshold = threshold
+	cfg.onSlow = fn
+	e.mailboxes.Store(&cfg)
+	return e
+}
+
+// schedule 安排一次监听器调用：邮箱模式下投递到监听器的邮箱，否则直接提交到分发队列
+func (e *Emitter[E, T]) schedule(cfg *mailboxConfig[E], wrapper *listenerWrapper[E, T], ev *Event[E, T], task func()) error {
+	if cfg == nil {
+		return e.dispatch.submit(task)
+	}
+
+	box := wrapper.mailbox()
+	if cfg.threshold > 0 && cfg.onSlow != nil {
+		call := task
+		task = func() {
+			start := time.Now()
+			call()
+			if elapsed := time.Since(start); elapsed > cfg.threshold {
+				cfg.onSlow(SlowListener[E]{Event: ev.Name, Listener: wrapper.fn(), Elapsed: elapsed, Pending: box.pending()})
+			}
+		}
+	}
+
+	box.mu.Lock()
+	reported := false
+	for cfg.size > 0 && box.queue.Size() >= cfg.size {
+		if cfg.onSlow != nil && !reported {
+			reported = true
+			pending := box.queue.Size()
+			box.mu.Unlock()
+			cfg.onSlow(SlowListener[E]{Event: ev.Name, Listener: wrapper.fn(), Pending: pending, Full: true})
+			box.mu.Lock()
+			continue
+		}
+		switch cfg.policy {
+		case OverflowDrop:
+			box.mu.Unlock()
+			e.dispatch.dropped.Add(1)
+			return nil
+		case OverflowError:
+			box.mu.Unlock()
+			e.dispatch.dropped.Add(1)
+			return ErrQueueFull
+		default:
+			box.space.Wait()
+		}
+	}
+	box.queue.PushBack(task)
+	if !box.running {
+		box.running = true
+		e.dispatch.run(box.drain)
+	}
+	box.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,139 @@
+package emission
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMailboxOrder 测试邮箱模式下每个监听器按触发顺序接收事件
+func TestMailboxOrder(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.EnableMailbox(0, OverflowBlock)
+
+	var mu sync.Mutex
+	var got [2][]int
+	var overlap atomic.Bool
+	for i := range 2 {
+		var busy atomic.Bool
+		em.On("evt", func(args ...int) {
+			if busy.Swap(true) {
+				overlap.Store(true)
+			}
+			time.Sleep(time.Duration(args[0]%3) * time.Millisecond)
+			mu.Lock()
+			got[i] = append(got[i], args[0])
+			mu.Unlock()
+			busy.Store(false)
+		})
+	}
+
+	const n = 50
+	for i := range n {
+		em.Emit("evt", i)
+	}
+	waitUntil(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got[0]) == n && len(got[1]) == n
+	})
+
+	for i := range 2 {
+		if !slices.IsSorted(got[i]) {
+			t.Errorf("Listener %d received events out of order: %v", i, got[i])
+		}
+	}
+	if overlap.Load() {
+		t.Error("Calls to the same listener should not overlap in mailbox mode")
+	}
+}
+
+// TestMailboxListenersRunInParallel 测试不同监听器的邮箱并行执行
+func TestMailboxListenersRunInParallel(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.EnableMailbox(0, OverflowBlock)
+
+	var probe concurrencyProbe
+	for range 4 {
+		em.On("evt", probe.listener(20*time.Millisecond))
+	}
+	em.EmitWait("evt")
+
+	if m := probe.max.Load(); m < 2 {
+		t.Errorf("Different listeners should run in parallel, max concurrent %d", m)
+	}
+}
+
+// TestMailboxOverflow 测试邮箱已满时的溢出策略与慢监听器检测
+func TestMailboxOverflow(t *testing.T) {
+	em := NewEmitter[string, int]()
+	var slow []SlowListener[string]
+	var mu sync.Mutex
+	em.EnableMailbox(1, OverflowError).OnSlowListener(5*time.Millisecond, func(info SlowListener[string]) {
+		mu.Lock()
+		slow = append(slow, info)
+		mu.Unlock()
+	})
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	em.On("evt", func(args ...int) {
+		<-release
+		calls.Add(1)
+	})
+
+	if err := em.Emit("evt", 1); err != nil {
+		t.Fatalf("First emit should be delivered, got %v", err)
+	}
+	// 等待第一次投递开始执行，邮箱重新变为空
+	waitUntil(t, func() bool { return em.events["evt"][0].mailbox().pending() == 0 })
+	if err := em.Emit("evt", 2); err != nil {
+		t.Fatalf("Second emit should be queued, got %v", err)
+	}
+	if err := em.Emit("evt", 3); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Expected ErrQueueFull, got %v", err)
+	}
+	if d := em.Dropped(); d != 1 {
+		t.Errorf("Expected 1 dropped delivery, got %d", d)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	waitUntil(t, func() bool { return calls.Load() == 2 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(slow) < 2 || !slow[0].Full || slow[0].Event != "evt" || slow[0].Pending != 1 {
+		t.Fatalf("Expected full mailbox report first, got %+v", slow)
+	}
+	if slow[1].Full || slow[1].Elapsed < 5*time.Millisecond {
+		t.Errorf("Expected slow call report, got %+v", slow[1])
+	}
+}
+
+// TestMailboxConcurrencyLimit 测试邮箱模式仍受全局并发度限制
+func TestMailboxConcurrencyLimit(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetConcurrency(2).EnableMailbox(0, OverflowBlock)
+
+	var probe concurrencyProbe
+	for range 6 {
+		em.On("evt", probe.listener(5*time.Millisecond))
+	}
+	for range 3 {
+		em.Emit("evt")
+	}
+	waitUntil(t, func() bool { return probe.calls.Load() == 18 })
+	if m := probe.max.Load(); m > 2 {
+		t.Errorf("Max concurrent should be <= 2, got %d", m)
+	}
+
+	em.DisableMailbox()
+	em.EmitWait("evt")
+	if c := probe.calls.Load(); c != 24 {
+		t.Errorf("Expected 24 calls after disabling mailbox, got %d", c)
+	}
+}
@@ -0,0 +1,69 @@
+package emission
+
+// EmitMiddleware 定义包裹每次触发的中间件
+// 参数 ev: 事件上下文，中间件可以读取或修改 Name 与 Args
+// 参数 next: 继续执行中间件链，最终查找并执行监听器；不调用 next 即可拦截本次触发
+// 对于 Emit，next 在监听器入队后即返回；对于 EmitWait、EmitSync 与 EmitSyncResult，next 在监听器执行完成后返回
+// 返回的错误由 Emit 与 EmitWait 返回，或计入 EmitSyncResult 的 Errors，EmitSync 忽略该错误
+type EmitMiddleware[E comparable, T any] func(ev *Event[E, T], next func() error) error
+
+// ListenerMiddleware 定义包裹每次监听器调用的中间件
+// 参数 ev: 事件上下文
+// 参数 listener: 被调用的监听器函数或事件处理器，可用于日志或指标标识
+// 参数 next: 继续执行中间件链，最终调用监听器；不调用 next 即可跳过该监听器
+// 返回值与错误作为监听器的结果，由 EmitSyncResult 汇总
+type ListenerMiddleware[E comparable, T any] func(ev *Event[E, T], listener any, next func() (any, error)) (any, error)
+
+// UseEmit 添加 emit 中间件，先添加的中间件位于外层
+// 中间件对所有事件的 Emit、EmitWait、EmitSync 与 EmitSyncResult 生效，即使事件没有监听器
+func (e *Emitter[E, T]) UseEmit(mws ...EmitMiddleware[E, T]) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var chain []EmitMiddleware[E, T]
+	if old := e.emitMiddleware.Load(); old != nil {
+		chain = append(chain, *old...)
+	}
+	chain = append(chain, mws...)
+	e.emitMiddleware.Store(&chain)
+	return e
+}
+
+// UseListener 添加监听器中间件，先添加的中间件位于外层
+// 中间件包裹所有监听器、事件处理器与订阅的每次调用，在执行监听器的协程中运行
+func (e *Emitter[E, T]) UseListener(mws ...ListenerMiddleware[E, T]) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var chain []ListenerMiddleware[E, T]
+	if old := e.listenerMiddleware.Load(); old != nil {
+		chain = append(chain, *old...)
+	}
+	chain = append(chain, mws...)
+	e.listenerMiddleware.Store(&chain)
+	return e
+}
+
+// intercept 依次经过 emit 中间件链与事件的防抖、节流或合并策略后执行 core
+func (e *Emitter[E, T]) intercept(ev *Event[E, T], core func(ev *Event[E, T]) error) error {
+	if limits := e.limits.Load(); limits != nil {
+		core = e.limited(*limits, core)
+	}
+	mws := e.emitMiddleware.Load()
+	if mws == nil {
+		return core(ev)
+	}
+	next := func() error { return core(ev) }
+	for i := len(*mws) - 1; i >= 0; i-- {
+		mw, inner := (*mws)[i], next
+		next = func() error { return mw(ev, inner) }
+	}
+	return next()
+}
+
+// chainListener 将监听器中间件链包裹在 invoke 外层
+func chainListener[E comparable, T any](mws []ListenerMiddleware[E, T], ev *Event[E, T], listener any, invoke func() (any, error)) func() (any, error) {
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw, inner := mws[i], invoke
+		invoke = func() (any, error) { return mw(ev, listener, inner) }
+	}
+	return invoke
+}
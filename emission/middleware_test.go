@@ -0,0 +1,118 @@
+package emission
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestEmitMiddlewareOrder 测试 emit 中间件按注册顺序由外到内执行
+func TestEmitMiddlewareOrder(t *testing.T) {
+	em := NewEmitter[string, int]()
+	var order []string
+	trace := func(name string) EmitMiddleware[string, int] {
+		return func(ev *Event[string, int], next func() error) error {
+			order = append(order, name+">")
+			err := next()
+			order = append(order, "<"+name)
+			return err
+		}
+	}
+	em.UseEmit(trace("outer"), trace("inner"))
+	em.On("evt", func(args ...int) { order = append(order, "listener") })
+
+	em.EmitSync("evt")
+	expected := []string{"outer>", "inner>", "listener", "<inner", "<outer"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("Expected %v, got %v", expected, order)
+	}
+}
+
+// TestEmitMiddlewareShortCircuit 测试 emit 中间件拦截触发并修改参数
+func TestEmitMiddlewareShortCircuit(t *testing.T) {
+	em := NewEmitter[string, int]()
+	errInvalid := errors.New("negative argument")
+	em.UseEmit(func(ev *Event[string, int], next func() error) error {
+		for i, v := range ev.Args {
+			if v < 0 {
+				return errInvalid
+			}
+			ev.Args[i] = v * 10
+		}
+		return next()
+	})
+
+	var got []int
+	em.On("evt", func(args ...int) { got = append(got, args...) })
+	em.Once("evt", func(args ...int) {})
+
+	if err := em.EmitWait("evt", -1); !errors.Is(err, errInvalid) {
+		t.Errorf("Expected validation error from EmitWait, got %v", err)
+	}
+	if err := em.Emit("evt", -1); !errors.Is(err, errInvalid) {
+		t.Errorf("Expected validation error from Emit, got %v", err)
+	}
+	if res := em.EmitSyncResult("evt", -1); !errors.Is(res.Err(), errInvalid) {
+		t.Errorf("Expected validation error in EmitSyncResult, got %v", res.Errors)
+	}
+	if len(got) != 0 {
+		t.Errorf("Listeners should not run when middleware short-circuits, got %v", got)
+	}
+	if count := em.GetListenerCount("evt"); count != 2 {
+		t.Errorf("Once listener should not be consumed by an intercepted emit, got %d listeners", count)
+	}
+
+	em.EmitSync("evt", 1, 2)
+	if !slices.Equal(got, []int{10, 20}) {
+		t.Errorf("Listeners should receive modified args, got %v", got)
+	}
+}
+
+// TestListenerMiddleware 测试监听器中间件包裹每次监听器调用
+func TestListenerMiddleware(t *testing.T) {
+	em := NewEmitter[string, int]()
+	var mu sync.Mutex
+	durations := map[string]time.Duration{}
+	em.UseListener(func(ev *Event[string, int], listener any, next func() (any, error)) (any, error) {
+		start := time.Now()
+		v, err := next()
+		mu.Lock()
+		durations[ev.Name] += time.Since(start)
+		mu.Unlock()
+		return v, err
+	})
+	// 过滤：跳过参数为 0 的调用
+	em.UseListener(func(ev *Event[string, int], listener any, next func() (any, error)) (any, error) {
+		if len(ev.Args) > 0 && ev.Args[0] == 0 {
+			return nil, nil
+		}
+		return next()
+	})
+
+	var calls int
+	em.On("slow", func(args ...int) {
+		calls++
+		time.Sleep(5 * time.Millisecond)
+	})
+	em.AddHandler("calc", func(ev *Event[string, int]) (any, error) {
+		return ev.Args[0] + 1, nil
+	})
+
+	em.EmitWait("slow", 1)
+	em.EmitSync("slow", 0)
+	if calls != 1 {
+		t.Errorf("Middleware should filter the second call, got %d calls", calls)
+	}
+	mu.Lock()
+	if durations["slow"] < 5*time.Millisecond {
+		t.Errorf("Middleware should observe listener timing, got %v", durations["slow"])
+	}
+	mu.Unlock()
+
+	res := em.EmitSyncResult("calc", 1)
+	if !slices.Equal(res.Results, []any{2}) {
+		t.Errorf("Middleware should pass handler results through, got %v", res.Results)
+	}
+}
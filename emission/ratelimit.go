@@ -0,0 +1,338 @@
+package emission
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wsshow/op/deque"
+)
+
+// Clock 抽象防抖、节流与合并使用的时间源，测试时可以替换为可控的实现
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+	// AfterFunc 在 d 之后于独立协程中调用 f
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer 是 Clock.AfterFunc 返回的定时器
+type Timer interface {
+	// Stop 取消定时器，定时器已触发或已取消时返回 false
+	Stop() bool
+}
+
+// realClock 是基于 time 包的默认时间源
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// DebounceMode 定义防抖在一串连续触发中执行的位置，可以按位组合
+type DebounceMode int
+
+const (
+	// DebounceTrailing 在连续触发停止 wait 之后，以最后一次触发执行（默认）
+	DebounceTrailing DebounceMode = 1 << iota
+	// DebounceLeading 立即执行一串连续触发中的第一次，其余触发被忽略
+	DebounceLeading
+)
+
+// limiter 决定一次触发立即执行、延迟执行还是被丢弃
+type limiter[E comparable, T any] interface {
+	// submit 提交一次触发，立即执行时调用 now 并返回其错误，延迟执行时稍后在时钟回调中调用 later
+	submit(ev *Event[E, T], now func(*Event[E, T]) error, later func(*Event[E, T])) error
+	// stop 取消尚未执行的延迟触发
+	stop()
+}
+
+// SetClock 设置防抖、节流与合并使用的时间源
+// 参数 clock: 时间源，nil 表示恢复为系统时间
+// 只影响之后配置的 Debounce、Throttle、Coalesce 及其监听器版本
+func (e *Emitter[E, T]) SetClock(clock Clock) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if clock == nil {
+		clock = realClock{}
+	}
+	e.clock = clock
+	return e
+}
+
+// Debounce 对事件的触发进行防抖：一串间隔小于 wait 的连续触发只执行一次
+// 参数 event: 事件标识
+// 参数 wait: 连续触发的判定间隔
+// 参数 mode: 执行连续触发中的第一次（DebounceLeading）、最后一次（DebounceTrailing）或两者都执行
+// 同一事件只能设置一种防抖、节流或合并策略，后设置的策略替换之前的策略
+// 策略位于 emit 中间件链的最内层，被延迟的触发在时钟回调中以 Emit 的方式异步投递，
+// 此时 EmitWait、EmitSync 与 EmitSyncResult 不等待监听器立即返回
+func (e *Emitter[E, T]) Debounce(event E, wait time.Duration, mode DebounceMode) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.setLimit(event, newDebouncer[E, T](e.clock, wait, mode))
+	return e
+}
+
+// Throttle 对事件的触发进行节流：任意 interval 时间窗口内最多执行 n 次，超出的触发被丢弃
+// 参数 event: 事件标识
+// 参数 n: 每个时间窗口内允许执行的次数，n <= 0 时按 1 处理
+// 参数 interval: 滑动时间窗口的长度
+// 未被丢弃的触发按原方式立即执行
+func (e *Emitter[E, T]) Throttle(event E, n int, interval time.Duration) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.setLimit(event, newThrottler[E, T](e.clock, n, interval))
+	return e
+}
+
+// Coalesce 合并事件在时间窗口内的触发：窗口从第一次触发开始，结束时以合并后的参数执行一次
+// 参数 event: 事件标识
+// 参数 window: 时间窗口的长度
+// 参数 merge: 将一次触发的参数 args 合并到已累积的参数 acc 中，nil 表示依次拼接所有参数
+// 合并后的触发在时钟回调中以 Emit 的方式异步投递
+func (e *Emitter[E, T]) Coalesce(event E, window time.Duration, merge func(acc, args []T) []T) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.setLimit(event, newCoalescer[E, T](e.clock, window, merge))
+	return e
+}
+
+// ClearRateLimit 移除事件的防抖、节流或合并策略，尚未执行的延迟触发被丢弃
+// 参数 event: 事件标识
+func (e *Emitter[E, T]) ClearRateLimit(event E) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.setLimit(event, nil)
+	return e
+}
+
+// DebounceListener 返回对 listener 防抖后的监听器，语义同 Debounce
+// 返回的监听器可以注册到任意事件，延迟的调用在时钟回调协程中执行，不经过 panic 恢复
+func (e *Emitter[E, T]) DebounceListener(wait time.Duration, mode DebounceMode, listener Listener[T]) Listener[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return limitListener(newDebouncer[E, T](e.clock, wait, mode), listener)
+}
+
+// ThrottleListener 返回对 listener 节流后的监听器，语义同 Throttle
+func (e *Emitter[E, T]) ThrottleListener(n int, interval time.Duration, listener Listener[T]) Listener[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return limitListener(newThrottler[E, T](e.clock, n, interval), listener)
+}
+
+// CoalesceListener 返回合并调用后的监听器，语义同 Coalesce
+// 合并后的调用在时钟回调协程中执行，不经过 panic 恢复
+func (e *Emitter[E, T]) CoalesceListener(window time.Duration, merge func(acc, args []T) []T, listener Listener[T]) Listener[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return limitListener(newCoalescer[E, T](e.clock, window, merge), listener)
+}
+
+// setLimit 在持有锁的情况下替换事件的策略，l 为 nil 表示移除
+func (e *Emitter[E, T]) setLimit(event E, l limiter[E, T]) {
+	var limits map[E]limiter[E, T]
+	if old := e.limits.Load(); old != nil {
+		if prev, ok := (*old)[event]; ok {
+			prev.stop()
+		}
+		limits = maps.Clone(*old)
+	} else {
+		limits = make(map[E]limiter[E, T])
+	}
+	if l == nil {
+		delete(limits, event)
+	} else {
+		limits[event] = l
+	}
+	if len(limits) == 0 {
+		e.limits.Store(nil)
+		return
+	}
+	e.limits.Store(&limits)
+}
+
+// limited 将事件策略包裹在 core 外层，按中间件处理后的事件标识查找策略
+func (e *Emitter[E, T]) limited(limits map[E]limiter[E, T], core func(ev *Event[E, T]) error) func(ev *Event[E, T]) error {
+	return func(ev *Event[E, T]) error {
+		l, ok := limits[ev.Name]
+		if !ok {
+			return core(ev)
+		}
+		return l.submit(ev, core, func(ev *Event[E, T]) { e.emitAsync(ev) })
+	}
+}
+
+// limitListener 以策略 l 包裹监听器
+func limitListener[E comparable, T any](l limiter[E, T], listener Listener[T]) Listener[T] {
+	call := func(ev *Event[E, T]) { listener(ev.Args...) }
+	return func(args ...T) {
+		l.submit(&Event[E, T]{Args: args}, func(ev *Event[E, T]) error {
+			call(ev)
+			return nil
+		}, call)
+	}
+}
+
+// deferEvent 复制一次触发用于延迟执行，避免调用方在触发后修改参数切片
+func deferEvent[E comparable, T any](ev *Event[E, T]) *Event[E, T] {
+	return newEvent(ev.Name, slices.Clone(ev.Args))
+}
+
+// debouncer 实现 Debounce
+type debouncer[E comparable, T any] struct {
+	clock Clock
+	wait  time.Duration
+	mode  DebounceMode
+
+	mu      sync.Mutex
+	timer   Timer              // 当前连续触发的计时器，nil 表示空闲
+	gen     uint64             // 计时器代数，用于忽略已被替换的计时器回调
+	pending *Event[E, T]       // 等待在连续触发结束时执行的触发
+	later   func(*Event[E, T]) // pending 的执行方式
+}
+
+func newDebouncer[E comparable, T any](clock Clock, wait time.Duration, mode DebounceMode) *debouncer[E, T] {
+	if mode&(DebounceLeading|DebounceTrailing) == 0 {
+		mode = DebounceTrailing
+	}
+	return &debouncer[E, T]{clock: clock, wait: wait, mode: mode}
+}
+
+func (d *debouncer[E, T]) submit(ev *Event[E, T], now func(*Event[E, T]) error, later func(*Event[E, T])) error {
+	d.mu.Lock()
+	leading := d.timer == nil && d.mode&DebounceLeading != 0
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	if !leading && d.mode&DebounceTrailing != 0 {
+		d.pending, d.later = deferEvent(ev), later
+	}
+	d.gen++
+	gen := d.gen
+	d.timer = d.clock.AfterFunc(d.wait, func() { d.flush(gen) })
+	d.mu.Unlock()
+
+	if leading {
+		return now(ev)
+	}
+	return nil
+}
+
+// flush 在连续触发结束时执行等待中的触发
+func (d *debouncer[E, T]) flush(gen uint64) {
+	d.mu.Lock()
+	if gen != d.gen {
+		d.mu.Unlock()
+		return
+	}
+	ev, later := d.pending, d.later
+	d.timer, d.pending, d.later = nil, nil, nil
+	d.mu.Unlock()
+
+	if ev != nil {
+		later(ev)
+	}
+}
+
+func (d *debouncer[E, T]) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.gen++
+	d.timer, d.pending, d.later = nil, nil, nil
+}
+
+// throttler 实现 Throttle，记录滑动窗口内每次执行的时间
+type throttler[E comparable, T any] struct {
+	clock    Clock
+	n        int
+	interval time.Duration
+
+	mu   sync.Mutex
+	sent *deque.Deque[time.Time]
+}
+
+func newThrottler[E comparable, T any](clock Clock, n int, interval time.Duration) *throttler[E, T] {
+	return &throttler[E, T]{clock: clock, n: max(n, 1), interval: interval, sent: deque.New[time.Time]()}
+}
+
+func (t *throttler[E, T]) submit(ev *Event[E, T], now func(*Event[E, T]) error, _ func(*Event[E, T])) error {
+	t.mu.Lock()
+	current := t.clock.Now()
+	for t.sent.Size() > 0 && current.Sub(t.sent.Front()) >= t.interval {
+		t.sent.PopFront()
+	}
+	if t.sent.Size() >= t.n {
+		t.mu.Unlock()
+		return nil
+	}
+	t.sent.PushBack(current)
+	t.mu.Unlock()
+	return now(ev)
+}
+
+func (t *throttler[E, T]) stop() {}
+
+// coalescer 实现 Coalesce
+type coalescer[E comparable, T any] struct {
+	clock  Clock
+	window time.Duration
+	merge  func(acc, args []T) []T
+
+	mu      sync.Mutex
+	timer   Timer              // 当前时间窗口的计时器，nil 表示没有等待合并的触发
+	gen     uint64             // 计时器代数，用于忽略已取消的计时器回调
+	pending *Event[E, T]       // 已合并的触发
+	later   func(*Event[E, T]) // pending 的执行方式
+}
+
+func newCoalescer[E comparable, T any](clock Clock, window time.Duration, merge func(acc, args []T) []T) *coalescer[E, T] {
+	if merge == nil {
+		merge = func(acc, args []T) []T { return append(acc, args...) }
+	}
+	return &coalescer[E, T]{clock: clock, window: window, merge: merge}
+}
+
+func (c *coalescer[E, T]) submit(ev *Event[E, T], _ func(*Event[E, T]) error, later func(*Event[E, T])) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.later = later
+	if c.timer != nil {
+		c.pending.Args = c.merge(c.pending.Args, ev.Args)
+		return nil
+	}
+	c.pending = deferEvent(ev)
+	c.gen++
+	gen := c.gen
+	c.timer = c.clock.AfterFunc(c.window, func() { c.flush(gen) })
+	return nil
+}
+
+// flush 在时间窗口结束时执行合并后的触发
+func (c *coalescer[E, T]) flush(gen uint64) {
+	c.mu.Lock()
+	if gen != c.gen {
+		c.mu.Unlock()
+		return
+	}
+	ev, later := c.pending, c.later
+	c.timer, c.pending, c.later = nil, nil, nil
+	c.mu.Unlock()
+
+	later(ev)
+}
+
+func (c *coalescer[E, T]) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.gen++
+	c.timer, c.pending, c.later = nil, nil, nil
+}
@@ -0,0 +1,261 @@
+package emission
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 是可手动推进的时间源，到期的定时器在 Advance 中同步执行
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	at    time.Time
+	f     func()
+	done  bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance 推进时间并依次执行到期的定时器
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due []*fakeTimer
+	c.timers = slices.DeleteFunc(c.timers, func(t *fakeTimer) bool {
+		if t.done || t.at.After(c.now) {
+			return t.done
+		}
+		t.done = true
+		due = append(due, t)
+		return true
+	})
+	c.mu.Unlock()
+	for _, t := range due {
+		t.f()
+	}
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	if t.done {
+		return false
+	}
+	t.done = true
+	return true
+}
+
+// recorder 记录监听器收到的参数
+type recorder[T any] struct {
+	mu    sync.Mutex
+	calls [][]T
+}
+
+func (r *recorder[T]) listener(args ...T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, args)
+}
+
+func (r *recorder[T]) snapshot() [][]T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+func equalCalls[T comparable](a, b [][]T) bool {
+	return slices.EqualFunc(a, b, func(x, y []T) bool { return slices.Equal(x, y) })
+}
+
+// TestDebounceTrailing 测试连续触发停止后以最后一次触发执行
+func TestDebounceTrailing(t *testing.T) {
+	clock := newFakeClock()
+	em := NewEmitter[string, int]().SetClock(clock)
+	em.Debounce("change", 100*time.Millisecond, DebounceTrailing)
+	var rec recorder[int]
+	em.On("change", rec.listener)
+
+	em.EmitSync("change", 1)
+	clock.Advance(50 * time.Millisecond)
+	args := []int{2}
+	em.EmitSync("change", args...)
+	args[0] = 100 // 修改切片不影响延迟的触发
+	clock.Advance(50 * time.Millisecond)
+	if calls := rec.snapshot(); len(calls) != 0 {
+		t.Fatalf("Listener should not run while emissions keep arriving, got %v", calls)
+	}
+
+	clock.Advance(50 * time.Millisecond)
+	waitUntil(t, func() bool { return len(rec.snapshot()) == 1 })
+	if calls := rec.snapshot(); !equalCalls(calls, [][]int{{2}}) {
+		t.Errorf("Expected only the last emission [2], got %v", calls)
+	}
+
+	em.EmitSync("other", 3)
+	if calls := rec.snapshot(); len(calls) != 1 {
+		t.Errorf("Other events should not be affected, got %v", calls)
+	}
+}
+
+// TestDebounceLeading 测试立即执行第一次触发并可选择在结束时再执行最后一次
+func TestDebounceLeading(t *testing.T) {
+	clock := newFakeClock()
+	em := NewEmitter[string, int]().SetClock(clock)
+	em.Debounce("save", 100*time.Millisecond, DebounceLeading|DebounceTrailing)
+	var rec recorder[int]
+	em.On("save", rec.listener)
+
+	em.EmitSync("save", 1)
+	if calls := rec.snapshot(); !equalCalls(calls, [][]int{{1}}) {
+		t.Fatalf("Leading emission should run synchronously, got %v", calls)
+	}
+	em.EmitSync("save", 2)
+	em.EmitSync("save", 3)
+	clock.Advance(100 * time.Millisecond)
+	waitUntil(t, func() bool { return len(rec.snapshot()) == 2 })
+	if calls := rec.snapshot(); !equalCalls(calls, [][]int{{1}, {3}}) {
+		t.Errorf("Expected leading [1] and trailing [3], got %v", calls)
+	}
+
+	// 只执行第一次的监听器
+	var leading recorder[int]
+	em.On("burst", em.DebounceListener(100*time.Millisecond, DebounceLeading, leading.listener))
+	for i := 1; i <= 3; i++ {
+		em.EmitSync("burst", i)
+		clock.Advance(60 * time.Millisecond)
+	}
+	clock.Advance(100 * time.Millisecond)
+	em.EmitSync("burst", 4)
+	if calls := leading.snapshot(); !equalCalls(calls, [][]int{{1}, {4}}) {
+		t.Errorf("Expected the first emission of each burst, got %v", calls)
+	}
+}
+
+// TestThrottle 测试时间窗口内最多执行 n 次
+func TestThrottle(t *testing.T) {
+	clock := newFakeClock()
+	em := NewEmitter[string, int]().SetClock(clock)
+	em.Throttle("tick", 2, time.Second)
+	var rec recorder[int]
+	em.On("tick", rec.listener)
+
+	for i := 1; i <= 5; i++ {
+		em.EmitSync("tick", i)
+	}
+	clock.Advance(500 * time.Millisecond)
+	em.EmitSync("tick", 6)
+	clock.Advance(500 * time.Millisecond)
+	em.EmitSync("tick", 7)
+	if calls := rec.snapshot(); !equalCalls(calls, [][]int{{1}, {2}, {7}}) {
+		t.Errorf("Expected [1] [2] [7], got %v", calls)
+	}
+
+	var listener recorder[int]
+	throttled := em.ThrottleListener(1, time.Second, listener.listener)
+	em.On("a", throttled)
+	em.On("b", throttled)
+	em.EmitSync("a", 1)
+	em.EmitSync("b", 2)
+	clock.Advance(time.Second)
+	em.EmitSync("b", 3)
+	if calls := listener.snapshot(); !equalCalls(calls, [][]int{{1}, {3}}) {
+		t.Errorf("Throttled listener should share its limit across events, got %v", calls)
+	}
+}
+
+// TestCoalesce 测试时间窗口内的触发合并为一次执行
+func TestCoalesce(t *testing.T) {
+	clock := newFakeClock()
+	em := NewEmitter[string, string]().SetClock(clock)
+	em.Coalesce("files", 100*time.Millisecond, nil)
+	var rec recorder[string]
+	em.On("files", rec.listener)
+
+	em.Emit("files", "a.go")
+	em.EmitSync("files", "b.go", "c.go")
+	clock.Advance(50 * time.Millisecond)
+	em.EmitWait("files", "a.go")
+	if calls := rec.snapshot(); len(calls) != 0 {
+		t.Fatalf("Listener should not run before the window ends, got %v", calls)
+	}
+	clock.Advance(50 * time.Millisecond)
+	waitUntil(t, func() bool { return len(rec.snapshot()) == 1 })
+	if calls := rec.snapshot(); !equalCalls(calls, [][]string{{"a.go", "b.go", "c.go", "a.go"}}) {
+		t.Errorf("Expected concatenated args, got %v", calls)
+	}
+
+	// 自定义合并函数：去重
+	unique := func(acc, args []string) []string {
+		for _, a := range args {
+			if !slices.Contains(acc, a) {
+				acc = append(acc, a)
+			}
+		}
+		return acc
+	}
+	var merged recorder[string]
+	em.On("dirs", em.CoalesceListener(100*time.Millisecond, unique, merged.listener))
+	em.EmitSync("dirs", "x")
+	em.EmitSync("dirs", "x", "y")
+	clock.Advance(100 * time.Millisecond)
+	if calls := merged.snapshot(); !equalCalls(calls, [][]string{{"x", "y"}}) {
+		t.Errorf("Expected merged args [x y], got %v", calls)
+	}
+}
+
+// TestClearRateLimit 测试移除策略后丢弃等待中的触发并恢复立即执行
+func TestClearRateLimit(t *testing.T) {
+	clock := newFakeClock()
+	em := NewEmitter[string, int]().SetClock(clock)
+	em.Debounce("evt", 100*time.Millisecond, DebounceTrailing)
+	var rec recorder[int]
+	em.On("evt", rec.listener)
+
+	em.EmitSync("evt", 1)
+	em.ClearRateLimit("evt")
+	clock.Advance(100 * time.Millisecond)
+	em.EmitSync("evt", 2)
+	if calls := rec.snapshot(); !equalCalls(calls, [][]int{{2}}) {
+		t.Errorf("Pending emission should be dropped after ClearRateLimit, got %v", calls)
+	}
+}
+
+// TestDebounceRealClock 测试默认时间源下的防抖
+func TestDebounceRealClock(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.Debounce("evt", 10*time.Millisecond, DebounceTrailing)
+	var rec recorder[int]
+	em.On("evt", rec.listener)
+
+	for i := 1; i <= 5; i++ {
+		em.Emit("evt", i)
+	}
+	waitUntil(t, func() bool { return len(rec.snapshot()) == 1 })
+	time.Sleep(20 * time.Millisecond)
+	if calls := rec.snapshot(); !equalCalls(calls, [][]int{{5}}) {
+		t.Errorf("Expected a single trailing call [5], got %v", calls)
+	}
+}
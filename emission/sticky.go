@@ -0,0 +1,128 @@
+package emission
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/wsshow/op/deque"
+)
+
+// stickyEvents 记录事件最近若干次触发的参数
+type stickyEvents[T any] struct {
+	limit int
+	args  *deque.Deque[[]T]
+}
+
+// SetSticky 将事件设为粘性事件，记录最近 n 次触发的参数
+// 参数 event: 事件标识
+// 参数 n: 记录的触发次数，n <= 0 表示取消粘性并清除已记录的触发
+// 之后通过 On、Once、AddHandler、Subscribe 等方式注册到该事件的监听器会在注册时按触发顺序回放记录：
+// 普通监听器回放全部记录，一次性监听器只以最近一次触发执行且不再注册
+// 回放在注册方协程中同步执行，不经过 emit 中间件；通配符模式与 OnAny 监听器不回放
+// 回放期间到达该监听器的实时触发会暂存到回放结束后，在注册方协程中按到达顺序投递，
+// 因此新监听器总是先收到回放的记录；这些暂存的投递不计入 EmitWait 的等待与 EmitSyncResult 的结果
+// 注意：使用 BufferBlock 策略订阅粘性事件时，缓冲区应能容纳回放的记录，否则 Subscribe 会阻塞
+func (e *Emitter[E, T]) SetSticky(event E, n int) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if n <= 0 {
+		delete(e.sticky, event)
+		return e
+	}
+	if e.sticky == nil {
+		e.sticky = make(map[E]*stickyEvents[T])
+	}
+	st, ok := e.sticky[event]
+	if !ok {
+		st = &stickyEvents[T]{args: deque.New[[]T]()}
+		e.sticky[event] = st
+	}
+	st.limit = n
+	for st.args.Size() > n {
+		st.args.PopFront()
+	}
+	return e
+}
+
+// ClearSticky 清除事件已记录的粘性触发，事件仍保持粘性
+// 参数 event: 事件标识
+func (e *Emitter[E, T]) ClearSticky(event E) *Emitter[E, T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if st, ok := e.sticky[event]; ok {
+		st.args.Clear()
+	}
+	return e
+}
+
+// record 在持有锁的情况下记录一次粘性触发，超出上限时丢弃最早的记录
+func (e *Emitter[E, T]) record(event E, args []T) {
+	st, ok := e.sticky[event]
+	if !ok {
+		return
+	}
+	// 复制参数，避免调用方在触发后修改切片影响回放
+	st.args.PushBack(slices.Clone(args))
+	if st.args.Size() > st.limit {
+		st.args.PopFront()
+	}
+}
+
+// stickyArgs 在持有锁的情况下按触发顺序返回事件已记录的参数
+func (e *Emitter[E, T]) stickyArgs(event E) [][]T {
+	st, ok := e.sticky[event]
+	if !ok || st.args.Size() == 0 {
+		return nil
+	}
+	replay := make([][]T, st.args.Size())
+	for i := range replay {
+		replay[i] = st.args.At(i)
+	}
+	return replay
+}
+
+// replay 依次以记录的参数调用监听器
+func (e *Emitter[E, T]) replay(event E, wrapper *listenerWrapper[E, T], replay [][]T, recoverer RecoveryListener[E, T]) {
+	for _, args := range replay {
+		e.invokeListener(newEvent(event, args), wrapper, recoverer)
+	}
+}
+
+// replayGate 暂存监听器回放粘性触发期间到达的实时触发，保证回放的记录先于实时触发投递
+type replayGate struct {
+	mu      sync.Mutex
+	pending []func()
+	done    bool // 回放及暂存的触发已全部投递
+}
+
+// hold 在回放结束前暂存一次投递并返回 true，回放已结束时返回 false
+func (g *replayGate) hold(deliver func()) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.done {
+		return false
+	}
+	g.pending = append(g.pending, deliver)
+	return true
+}
+
+// releaseGate 在回放结束后按到达顺序投递暂存的触发，并移除监听器的暂存
+// 投递期间新到达的触发继续暂存，直到暂存为空
+func (e *Emitter[E, T]) releaseGate(wrapper *listenerWrapper[E, T]) {
+	gate := wrapper.gate.Load()
+	for {
+		gate.mu.Lock()
+		pending := gate.pending
+		gate.pending = nil
+		if len(pending) == 0 {
+			gate.done = true
+			gate.mu.Unlock()
+			break
+		}
+		gate.mu.Unlock()
+		for _, deliver := range pending {
+			deliver()
+		}
+	}
+	wrapper.gate.Store(nil)
+}
@@ -0,0 +1,142 @@
+package emission
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestStickyReplay 测试新监听器按触发顺序回放最近的粘性触发
+func TestStickyReplay(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetSticky("config", 2)
+
+	em.EmitSync("config", 1)
+	em.EmitWait("config", 2)
+	args := []int{3}
+	em.EmitSync("config", args...)
+	args[0] = 100 // 修改切片不影响已记录的触发
+
+	var got []int
+	em.On("config", func(a ...int) { got = append(got, a...) })
+	if !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Expected replay of last 2 emissions [2 3], got %v", got)
+	}
+
+	em.EmitSync("config", 4)
+	if !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("Listener should receive new emissions after replay, got %v", got)
+	}
+
+	var other []int
+	em.On("other", func(a ...int) { other = append(other, a...) })
+	if len(other) != 0 {
+		t.Errorf("Non-sticky events should not replay, got %v", other)
+	}
+}
+
+// TestStickyOnce 测试一次性监听器只以最近一次粘性触发执行
+func TestStickyOnce(t *testing.T) {
+	em := NewEmitter[string, string]()
+	em.SetSticky("ready", 3)
+
+	var got []string
+	em.Once("ready", func(a ...string) { got = append(got, a...) })
+	em.EmitSync("ready", "first")
+	em.EmitSync("ready", "second")
+	if !slices.Equal(got, []string{"first"}) {
+		t.Errorf("Once listener registered before emit should run once, got %v", got)
+	}
+
+	got = nil
+	em.Once("ready", func(a ...string) { got = append(got, a...) })
+	if !slices.Equal(got, []string{"second"}) {
+		t.Errorf("Late once listener should replay the latest emission, got %v", got)
+	}
+	if count := em.GetListenerCount("ready"); count != 0 {
+		t.Errorf("Replayed once listener should not be registered, got %d", count)
+	}
+}
+
+// TestClearSticky 测试清除与取消粘性触发
+func TestClearSticky(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetSticky("evt", 5)
+	em.EmitSync("evt", 1)
+	em.ClearSticky("evt")
+
+	var got []int
+	em.On("evt", func(a ...int) { got = append(got, a...) })
+	if len(got) != 0 {
+		t.Errorf("Cleared sticky event should not replay, got %v", got)
+	}
+
+	em.EmitSync("evt", 2)
+	em.SetSticky("evt", 0)
+	em.On("evt", func(a ...int) { got = append(got, a...) })
+	if !slices.Equal(got, []int{2}) {
+		t.Errorf("Disabled sticky event should not replay, got %v", got)
+	}
+}
+
+// TestStickySubscribe 测试订阅同样回放粘性触发
+func TestStickySubscribe(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetSticky("evt", 1)
+	em.EmitSync("evt", 7)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := em.Subscribe(ctx, "evt", 1)
+	if got := <-ch; !slices.Equal(got, []int{7}) {
+		t.Errorf("Subscription should replay sticky emission, got %v", got)
+	}
+}
+
+// TestStickyReplayBeforeLive 测试回放期间到达的实时触发在回放结束后才投递
+func TestStickyReplayBeforeLive(t *testing.T) {
+	em := NewEmitter[string, int]()
+	em.SetSticky("evt", 3)
+	for i := 1; i <= 3; i++ {
+		em.EmitSync("evt", i)
+	}
+
+	var mu sync.Mutex
+	var got []int
+	replaying := make(chan struct{})
+	proceed := make(chan struct{})
+	registered := make(chan struct{})
+	go func() {
+		em.On("evt", func(args ...int) {
+			if args[0] == 1 {
+				close(replaying)
+				<-proceed
+			}
+			mu.Lock()
+			got = append(got, args...)
+			mu.Unlock()
+		})
+		close(registered)
+	}()
+
+	<-replaying
+	if count := em.GetListenerCount("evt"); count != 1 {
+		t.Fatalf("Listener should be registered during replay, got %d", count)
+	}
+	em.EmitSync("evt", 4)
+	em.Emit("evt", 5)
+	close(proceed)
+	<-registered
+
+	waitUntil(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 5
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Replayed emissions should precede live ones, got %v", got)
+	}
+}
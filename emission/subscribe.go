@@ -0,0 +1,156 @@
+package emission
+
+import (
+	"context"
+	"sync"
+)
+
+// BufferPolicy 定义订阅通道缓冲区已满时的处理策略
+type BufferPolicy int
+
+const (
+	// BufferBlock 阻塞监听器直到订阅方读取或取消订阅（默认）
+	BufferBlock BufferPolicy = iota
+	// BufferDrop 丢弃新事件
+	BufferDrop
+	// BufferDropOldest 丢弃缓冲区中最早的事件，为新事件腾出空间
+	BufferDropOldest
+)
+
+// SubscribeOption 定义订阅的可选配置函数
+type SubscribeOption func(*subscribeConfig)
+
+// subscribeConfig 是订阅的配置
+type subscribeConfig struct {
+	policy BufferPolicy
+}
+
+// WithBufferPolicy 设置订阅通道缓冲区已满时的处理策略
+func WithBufferPolicy(policy BufferPolicy) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.policy = policy
+	}
+}
+
+// Message 是多事件订阅通道中的元素
+type Message[E comparable, T any] struct {
+	Event E   // 事件标识
+	Args  []T // 触发事件时传入的参数
+}
+
+// subscription 将监听器调用转发到通道
+type subscription[M any] struct {
+	mu     sync.Mutex
+	ch     chan M
+	closed bool
+	policy BufferPolicy
+	done   <-chan struct{}
+}
+
+// send 按缓冲策略将元素写入通道，返回元素是否被丢弃
+func (s *subscription[M]) send(m M) (dropped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+
+	switch s.policy {
+	case BufferDrop:
+		select {
+		case s.ch <- m:
+			return false
+		default:
+			return true
+		}
+	case BufferDropOldest:
+		for {
+			select {
+			case s.ch <- m:
+				return dropped
+			default:
+			}
+			// 只有本订阅向通道写入，此处读取到的一定是缓冲区中最早的元素
+			select {
+			case <-s.ch:
+				dropped = true
+			default:
+			}
+		}
+	default:
+		select {
+		case s.ch <- m:
+		case <-s.done:
+		}
+		return false
+	}
+}
+
+// close 关闭通道，之后的 send 不再写入
+func (s *subscription[M]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	close(s.ch)
+}
+
+// Subscribe 以通道形式订阅事件，通道中的元素为每次触发时传入的参数
+// 参数 ctx: 订阅的生命周期，ctx 结束后自动取消订阅并关闭通道
+// 参数 event: 事件标识
+// 参数 bufferSize: 通道缓冲区大小，缓冲区已满时按 WithBufferPolicy 设置的策略处理
+// 订阅以普通监听器的形式注册，计入 GetListenerCount；丢弃的事件计入 SubscriberDropped
+// 注意：Emit 与 EmitWait 并发调用监听器，需要按触发顺序接收时应使用 EmitSync 或邮箱模式
+func (e *Emitter[E, T]) Subscribe(ctx context.Context, event E, bufferSize int, opts ...SubscribeOption) <-chan []T {
+	return subscribe(ctx, e, []E{event}, bufferSize, opts, func(_ E, args []T) []T {
+		return args
+	})
+}
+
+// SubscribeEvents 以通道形式同时订阅多个事件，通道中的元素包含实际触发的事件标识与参数
+// 参数与行为同 Subscribe
+func (e *Emitter[E, T]) SubscribeEvents(ctx context.Context, events []E, bufferSize int, opts ...SubscribeOption) <-chan Message[E, T] {
+	return subscribe(ctx, e, events, bufferSize, opts, func(event E, args []T) Message[E, T] {
+		return Message[E, T]{Event: event, Args: args}
+	})
+}
+
+// SubscriberDropped 返回因订阅通道缓冲区已满而被丢弃的事件数
+func (e *Emitter[E, T]) SubscriberDropped() uint64 {
+	return e.subDropped.Load()
+}
+
+// subscribe 是 Subscribe 与 SubscribeEvents 的公共实现，convert 将事件转换为通道元素
+func subscribe[E comparable, T, M any](ctx context.Context, e *Emitter[E, T], events []E, bufferSize int, opts []SubscribeOption, convert func(event E, args []T) M) <-chan M {
+	var cfg subscribeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	bufferSize = max(bufferSize, 0)
+	if cfg.policy == BufferDropOldest {
+		// 丢弃最早的事件需要缓冲区
+		bufferSize = max(bufferSize, 1)
+	}
+
+	s := &subscription[M]{
+		ch:     make(chan M, bufferSize),
+		policy: cfg.policy,
+		done:   ctx.Done(),
+	}
+	cancels := make([]func(), 0, len(events))
+	for _, event := range events {
+		cancels = append(cancels, e.AddHandler(event, func(ev *Event[E, T]) (any, error) {
+			if s.send(convert(ev.Name, ev.Args)) {
+				e.subDropped.Add(1)
+			}
+			return nil, nil
+		}))
+	}
+
+	context.AfterFunc(ctx, func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+		s.close()
+	})
+	return s.ch
+}
@@ -0,0 +1,109 @@
+package emission
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestSubscribe 测试通道订阅与取消订阅
+func TestSubscribe(t *testing.T) {
+	em := NewEmitter[string, int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := em.Subscribe(ctx, "evt", 4)
+
+	em.EmitSync("evt", 1, 2)
+	em.EmitSync("evt", 3)
+	if got := <-ch; !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Expected [1 2], got %v", got)
+	}
+	if got := <-ch; !slices.Equal(got, []int{3}) {
+		t.Errorf("Expected [3], got %v", got)
+	}
+	if count := em.GetListenerCount("evt"); count != 1 {
+		t.Errorf("Subscription should register one listener, got %d", count)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Channel should be closed after context is done")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for channel to close")
+	}
+	if count := em.GetListenerCount("evt"); count != 0 {
+		t.Errorf("Subscription should be removed after context is done, got %d", count)
+	}
+	em.EmitSync("evt", 4) // 取消订阅后触发不应 panic
+}
+
+// TestSubscribeEvents 测试多事件通道订阅
+func TestSubscribeEvents(t *testing.T) {
+	em := NewEmitter[string, string]()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := em.SubscribeEvents(ctx, []string{"a", "b"}, 3)
+
+	em.EmitSync("a", "x")
+	em.EmitSync("c", "ignored")
+	em.EmitSync("b", "y")
+
+	expected := []Message[string, string]{{"a", []string{"x"}}, {"b", []string{"y"}}}
+	for _, want := range expected {
+		got := <-ch
+		if got.Event != want.Event || !slices.Equal(got.Args, want.Args) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+// TestSubscribeBufferPolicies 测试缓冲区已满时的处理策略
+func TestSubscribeBufferPolicies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	em := NewEmitter[string, int]()
+	drop := em.Subscribe(ctx, "evt", 2, WithBufferPolicy(BufferDrop))
+	oldest := em.Subscribe(ctx, "evt", 2, WithBufferPolicy(BufferDropOldest))
+	for i := range 5 {
+		em.EmitSync("evt", i)
+	}
+
+	if got := []int{(<-drop)[0], (<-drop)[0]}; !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("BufferDrop should keep the first events, got %v", got)
+	}
+	if got := []int{(<-oldest)[0], (<-oldest)[0]}; !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("BufferDropOldest should keep the latest events, got %v", got)
+	}
+	if d := em.SubscriberDropped(); d != 6 {
+		t.Errorf("Expected 6 dropped events, got %d", d)
+	}
+}
+
+// TestSubscribeBlockUnblocksOnCancel 测试阻塞策略下取消订阅会释放被阻塞的监听器
+func TestSubscribeBlockUnblocksOnCancel(t *testing.T) {
+	em := NewEmitter[string, int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := em.Subscribe(ctx, "evt", 0)
+
+	done := make(chan struct{})
+	go func() {
+		em.EmitSync("evt", 1)
+		em.EmitSync("evt", 2)
+		close(done)
+	}()
+
+	if got := <-ch; got[0] != 1 {
+		t.Errorf("Expected 1, got %v", got)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Blocked listener should return after the subscription is cancelled")
+	}
+}
@@ -0,0 +1,184 @@
+package emission
+
+import (
+	"strings"
+)
+
+// TopicSeparator 是主题中各级名称的分隔符
+const TopicSeparator = "."
+
+const (
+	topicWildcardOne  = "*"  // 匹配恰好一级
+	topicWildcardMany = "#"  // 匹配零级或多级
+	topicWildcardDeep = "**" // topicWildcardMany 的别名
+)
+
+// matcher 定义通配符匹配器，由 Emitter 在持有锁的情况下调用
+type matcher[E comparable, T any] interface {
+	// accepts 判断 event 是否为通配符模式，是则由匹配器管理其监听器
+	accepts(event E) bool
+	// add 将监听器添加到模式下
+	add(pattern E, wrapper *listenerWrapper[E, T])
+	// remove 从模式下移除指定 ID 的监听器
+	remove(pattern E, id uint64)
+	// removeAll 移除模式下的所有监听器
+	removeAll(pattern E)
+	// count 返回模式下的监听器数量
+	count(pattern E) int
+	// match 将匹配 event 的监听器追加到 dst，并移除其中的 once 监听器
+	match(event E, dst []*listenerWrapper[E, T]) []*listenerWrapper[E, T]
+}
+
+// NewTopicEmitter 创建一个支持通配符订阅的主题事件发射器
+// 主题由 TopicSeparator 分隔为多级，例如 "order.created"
+// 注册监听器时可使用通配符模式：
+//   - "*" 匹配恰好一级，例如 "order.*" 匹配 "order.created"，不匹配 "order.item.added"
+//   - "#" 或 "**" 匹配零级或多级，例如 "order.#" 匹配 "order"、"order.created" 与 "order.item.added"
+//
+// 通配符模式保存在前缀树中，触发事件时的查找开销与主题层级数相关，而与模式数量无关
+// 返回的 Emitter 的其他功能（优先级、一次性监听器、事件处理器等）同样适用于通配符模式
+func NewTopicEmitter[T any]() *Emitter[string, T] {
+	e := NewEmitter[string, T]()
+	e.patterns = &topicTrie[T]{}
+	return e
+}
+
+// topicNode 是主题前缀树的节点，每个节点对应模式中的一级
+type topicNode[T any] struct {
+	children  map[string]*topicNode[T]
+	listeners []*listenerWrapper[string, T]
+}
+
+// topicTrie 是基于前缀树的通配符匹配器
+type topicTrie[T any] struct {
+	root topicNode[T]
+}
+
+// splitTopic 将主题拆分为各级名称，并将 "**" 统一为 "#"
+func splitTopic(topic string) []string {
+	segments := strings.Split(topic, TopicSeparator)
+	for i, seg := range segments {
+		if seg == topicWildcardDeep {
+			segments[i] = topicWildcardMany
+		}
+	}
+	return segments
+}
+
+// accepts 判断主题中是否包含通配符级
+func (t *topicTrie[T]) accepts(pattern string) bool {
+	for _, seg := range strings.Split(pattern, TopicSeparator) {
+		if seg == topicWildcardOne || seg == topicWildcardMany || seg == topicWildcardDeep {
+			return true
+		}
+	}
+	return false
+}
+
+// find 查找模式对应的节点，create 为 true 时创建缺失的节点
+// 返回从根节点开始的路径，未找到时返回 nil
+func (t *topicTrie[T]) find(pattern string, create bool) []*topicNode[T] {
+	node := &t.root
+	path := []*topicNode[T]{node}
+	for _, seg := range splitTopic(pattern) {
+		child, ok := node.children[seg]
+		if !ok {
+			if !create {
+				return nil
+			}
+			if node.children == nil {
+				node.children = make(map[string]*topicNode[T])
+			}
+			child = &topicNode[T]{}
+			node.children[seg] = child
+		}
+		node = child
+		path = append(path, node)
+	}
+	return path
+}
+
+// add 将监听器添加到模式对应的节点
+func (t *topicTrie[T]) add(pattern string, wrapper *listenerWrapper[string, T]) {
+	path := t.find(pattern, true)
+	node := path[len(path)-1]
+	node.listeners = insertListener(node.listeners, wrapper)
+}
+
+// remove 从模式对应的节点移除监听器，并清理不再使用的节点
+func (t *topicTrie[T]) remove(pattern string, id uint64) {
+	path := t.find(pattern, false)
+	if path == nil {
+		return
+	}
+	node := path[len(path)-1]
+	node.listeners = deleteListener(node.listeners, id)
+	t.prune(pattern, path)
+}
+
+// removeAll 移除模式对应节点的所有监听器，并清理不再使用的节点
+func (t *topicTrie[T]) removeAll(pattern string) {
+	path := t.find(pattern, false)
+	if path == nil {
+		return
+	}
+	path[len(path)-1].listeners = nil
+	t.prune(pattern, path)
+}
+
+// prune 自下而上删除路径上既没有监听器也没有子节点的节点
+func (t *topicTrie[T]) prune(pattern string, path []*topicNode[T]) {
+	segments := splitTopic(pattern)
+	for i := len(path) - 1; i > 0; i-- {
+		node := path[i]
+		if len(node.listeners) > 0 || len(node.children) > 0 {
+			return
+		}
+		delete(path[i-1].children, segments[i-1])
+	}
+}
+
+// count 返回模式对应节点的监听器数量
+func (t *topicTrie[T]) count(pattern string) int {
+	path := t.find(pattern, false)
+	if path == nil {
+		return 0
+	}
+	return len(path[len(path)-1].listeners)
+}
+
+// match 将匹配主题的所有模式下的监听器追加到 dst
+// 同一模式可能经由多条路径匹配（例如 "#.#"），由调用方去重
+func (t *topicTrie[T]) match(topic string, dst []*listenerWrapper[string, T]) []*listenerWrapper[string, T] {
+	if len(t.root.children) == 0 {
+		return dst
+	}
+	return t.matchNode(&t.root, strings.Split(topic, TopicSeparator), dst)
+}
+
+// matchNode 匹配从 node 开始的剩余级
+func (t *topicTrie[T]) matchNode(node *topicNode[T], segments []string, dst []*listenerWrapper[string, T]) []*listenerWrapper[string, T] {
+	if len(segments) == 0 {
+		if len(node.listeners) > 0 {
+			dst, node.listeners = takeListeners(dst, node.listeners)
+		}
+		// "#" 可以匹配零级
+		if many, ok := node.children[topicWildcardMany]; ok {
+			dst = t.matchNode(many, segments, dst)
+		}
+		return dst
+	}
+
+	if child, ok := node.children[segments[0]]; ok {
+		dst = t.matchNode(child, segments[1:], dst)
+	}
+	if one, ok := node.children[topicWildcardOne]; ok {
+		dst = t.matchNode(one, segments[1:], dst)
+	}
+	if many, ok := node.children[topicWildcardMany]; ok {
+		for i := 0; i <= len(segments); i++ {
+			dst = t.matchNode(many, segments[i:], dst)
+		}
+	}
+	return dst
+}
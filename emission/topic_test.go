@@ -0,0 +1,128 @@
+package emission
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// TestTopicWildcards 测试 "*"、"#" 与 "**" 通配符匹配
+func TestTopicWildcards(t *testing.T) {
+	cases := []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"order.*", "order.created", true},
+		{"order.*", "order.item.added", false},
+		{"order.*", "order", false},
+		{"*.created", "order.created", true},
+		{"order.#", "order", true},
+		{"order.#", "order.item.added", true},
+		{"order.**", "order.paid", true},
+		{"#", "anything.at.all", true},
+		{"**", "order", true},
+		{"order.#.added", "order.added", true},
+		{"order.#.added", "order.item.line.added", true},
+		{"order.#.added", "order.item.removed", false},
+		{"*.*", "order", false},
+		{"user.*", "order.created", false},
+	}
+
+	for _, c := range cases {
+		em := NewTopicEmitter[int]()
+		var called atomic.Int32
+		em.On(c.pattern, func(args ...int) { called.Add(1) })
+		em.EmitSync(c.topic)
+		if got := called.Load() == 1; got != c.match {
+			t.Errorf("Pattern %q on topic %q: expected match=%v, got %d calls", c.pattern, c.topic, c.match, called.Load())
+		}
+	}
+}
+
+// TestTopicEmitterOrder 测试精确与通配符监听器按优先级和注册顺序执行，且同一监听器只执行一次
+func TestTopicEmitterOrder(t *testing.T) {
+	em := NewTopicEmitter[string]()
+	var order []string
+	record := func(name string) Listener[string] {
+		return func(args ...string) { order = append(order, name) }
+	}
+
+	em.On("#", record("all"))
+	em.On("order.created", record("exact"))
+	em.On("order.*", record("star"))
+	em.AddListenerWithPriority("#.#", 10, record("audit"))
+	em.On("user.*", record("user"))
+
+	em.EmitSync("order.created")
+	expected := []string{"audit", "all", "exact", "star"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("Expected order %v, got %v", expected, order)
+	}
+}
+
+// TestTopicEmitterRemove 测试通配符监听器的移除与计数
+func TestTopicEmitterRemove(t *testing.T) {
+	em := NewTopicEmitter[int]()
+	var calls atomic.Int32
+	cancel := em.On("order.*", func(args ...int) { calls.Add(1) })
+	em.On("order.*", func(args ...int) { calls.Add(1) })
+	em.Once("order.#", func(args ...int) { calls.Add(1) })
+
+	if count := em.GetListenerCount("order.*"); count != 2 {
+		t.Errorf("Expected 2 listeners on pattern, got %d", count)
+	}
+	if count := em.GetListenerCount("order.created"); count != 0 {
+		t.Errorf("Exact topic should have no listeners, got %d", count)
+	}
+
+	em.EmitSync("order.created")
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls.Load())
+	}
+	if count := em.GetListenerCount("order.#"); count != 0 {
+		t.Errorf("Once pattern listener should be removed, got %d", count)
+	}
+
+	cancel()
+	em.EmitSync("order.created")
+	if calls.Load() != 4 {
+		t.Errorf("Expected 4 calls after cancel, got %d", calls.Load())
+	}
+
+	em.RemoveAllListeners("order.*")
+	em.EmitSync("order.created")
+	if calls.Load() != 4 {
+		t.Errorf("Expected no calls after RemoveAllListeners, got %d", calls.Load())
+	}
+	if _, ok := em.patterns.(*topicTrie[int]).root.children["order"].children["*"]; ok {
+		t.Error("Empty trie nodes should be pruned")
+	}
+}
+
+// TestOnAny 测试监听所有事件的监听器
+func TestOnAny(t *testing.T) {
+	em := NewEmitter[int, string]()
+	var events []int
+	var args []string
+	cancel := em.OnAny(func(event int, a ...string) {
+		events = append(events, event)
+		args = append(args, a...)
+	})
+	em.On(1, func(a ...string) {})
+
+	em.EmitSync(1, "a")
+	em.EmitSync(2, "b")
+	if !slices.Equal(events, []int{1, 2}) || !slices.Equal(args, []string{"a", "b"}) {
+		t.Errorf("Expected events [1 2] with args [a b], got %v %v", events, args)
+	}
+	if count := em.GetListenerCount(2); count != 0 {
+		t.Errorf("OnAny listeners should not be counted, got %d", count)
+	}
+
+	cancel()
+	em.EmitSync(3, "c")
+	if len(events) != 2 {
+		t.Errorf("OnAny listener should be removed, got events %v", events)
+	}
+}
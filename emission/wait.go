@@ -0,0 +1,106 @@
+package emission
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// WaitFor 等待事件的下一次满足条件的触发，返回该次触发的参数
+// 参数 ctx: 等待的期限，ctx 结束时返回 nil 与 ctx.Err()
+// 参数 event: 事件标识
+// 参数 predicate: 判断触发是否满足条件，nil 表示接受任意触发
+// 监听器在 WaitFor 返回前注册，因此调用 WaitFor 之后的触发不会被遗漏；返回时监听器已被移除
+// predicate 为 nil 时基于 Once 实现，并发触发中只有一次会被接收；粘性事件会立即以最近的记录返回
+func (e *Emitter[E, T]) WaitFor(ctx context.Context, event E, predicate func(args ...T) bool) ([]T, error) {
+	result := make(chan []T, 1)
+	var cancel func()
+	if predicate == nil {
+		cancel = e.OnceHandler(event, func(ev *Event[E, T]) (any, error) {
+			result <- ev.Args
+			return nil, nil
+		})
+	} else {
+		// 不满足条件的触发不能消耗监听器，改用普通监听器并保证只接收一次
+		var matched atomic.Bool
+		cancel = e.AddHandler(event, func(ev *Event[E, T]) (any, error) {
+			if predicate(ev.Args...) && matched.CompareAndSwap(false, true) {
+				result <- ev.Args
+			}
+			return nil, nil
+		})
+	}
+	defer cancel()
+
+	select {
+	case args := <-result:
+		return args, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// WaitForAny 等待 events 中任意一个事件的下一次触发
+// 参数 ctx: 等待的期限，ctx 结束时返回 ctx.Err()
+// 参数 events: 事件标识列表
+// 返回最先被接收的触发，Message.Event 为实际触发的事件标识
+func (e *Emitter[E, T]) WaitForAny(ctx context.Context, events []E) (Message[E, T], error) {
+	result := make(chan Message[E, T], 1)
+	var received atomic.Bool
+	cancels := make([]func(), 0, len(events))
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+	for _, event := range events {
+		cancels = append(cancels, e.OnceHandler(event, func(ev *Event[E, T]) (any, error) {
+			if received.CompareAndSwap(false, true) {
+				result <- Message[E, T]{Event: ev.Name, Args: ev.Args}
+			}
+			return nil, nil
+		}))
+	}
+
+	select {
+	case msg := <-result:
+		return msg, nil
+	case <-ctx.Done():
+		return Message[E, T]{}, ctx.Err()
+	}
+}
+
+// WaitForAll 等待 events 中每个事件都至少触发一次
+// 参数 ctx: 等待的期限，ctx 结束时返回 nil 与 ctx.Err()
+// 参数 events: 事件标识列表，重复的事件只等待一次
+// 返回每个事件第一次触发的参数，键为 events 中的事件标识
+func (e *Emitter[E, T]) WaitForAll(ctx context.Context, events []E) (map[E][]T, error) {
+	pending := make(map[E]struct{}, len(events))
+	for _, event := range events {
+		pending[event] = struct{}{}
+	}
+
+	result := make(chan Message[E, T], len(pending))
+	cancels := make([]func(), 0, len(pending))
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+	for event := range pending {
+		cancels = append(cancels, e.OnceHandler(event, func(ev *Event[E, T]) (any, error) {
+			result <- Message[E, T]{Event: event, Args: ev.Args}
+			return nil, nil
+		}))
+	}
+
+	got := make(map[E][]T, len(pending))
+	for len(got) < len(pending) {
+		select {
+		case msg := <-result:
+			got[msg.Event] = msg.Args
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return got, nil
+}
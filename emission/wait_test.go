@@ -0,0 +1,184 @@
+package emission
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWaitFor 测试等待满足条件的下一次触发
+func TestWaitFor(t *testing.T) {
+	em := NewEmitter[string, int]()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	go func() {
+		for i := 1; i <= 5; i++ {
+			time.Sleep(time.Millisecond)
+			em.EmitSync("progress", i)
+		}
+	}()
+
+	args, err := em.WaitFor(ctx, "progress", func(args ...int) bool { return args[0] >= 3 })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(args, []int{3}) {
+		t.Errorf("Expected first matching emission [3], got %v", args)
+	}
+	waitUntil(t, func() bool { return em.GetListenerCount("progress") == 0 })
+}
+
+// TestWaitForTimeout 测试 ctx 结束时返回错误并移除监听器
+func TestWaitForTimeout(t *testing.T) {
+	em := NewEmitter[string, int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	args, err := em.WaitFor(ctx, "never", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if args != nil {
+		t.Errorf("Expected nil args on timeout, got %v", args)
+	}
+	if count := em.GetListenerCount("never"); count != 0 {
+		t.Errorf("Listener should be removed after timeout, got %d", count)
+	}
+}
+
+// TestWaitForSticky 测试等待粘性事件时立即返回最近的记录
+func TestWaitForSticky(t *testing.T) {
+	em := NewEmitter[string, string]()
+	em.SetSticky("ready", 1)
+	em.EmitSync("ready", "ok")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	args, err := em.WaitFor(ctx, "ready", nil)
+	if err != nil || !slices.Equal(args, []string{"ok"}) {
+		t.Errorf("Expected sticky args [ok], got %v, %v", args, err)
+	}
+}
+
+// TestWaitForConcurrentEmit 测试并发触发时每个等待方只接收一次
+func TestWaitForConcurrentEmit(t *testing.T) {
+	em := NewEmitter[string, int]()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	const waiters = 10
+	var wg sync.WaitGroup
+	results := make(chan []int, waiters)
+	ready := make(chan struct{}, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func(even bool) {
+			defer wg.Done()
+			var predicate func(args ...int) bool
+			if even {
+				predicate = func(args ...int) bool { return args[0]%2 == 0 }
+			}
+			ready <- struct{}{}
+			args, err := em.WaitFor(ctx, "tick", predicate)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			results <- args
+		}(i%2 == 0)
+	}
+	for i := 0; i < waiters; i++ {
+		<-ready
+	}
+	waitUntil(t, func() bool { return em.GetListenerCount("tick") == waiters })
+
+	var emitters sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		emitters.Add(1)
+		go func(v int) {
+			defer emitters.Done()
+			em.EmitWait("tick", v)
+		}(i)
+	}
+	emitters.Wait()
+	wg.Wait()
+	close(results)
+
+	count := 0
+	for args := range results {
+		if len(args) != 1 {
+			t.Errorf("Expected a single arg, got %v", args)
+		}
+		count++
+	}
+	if count != waiters {
+		t.Errorf("Expected %d results, got %d", waiters, count)
+	}
+	if n := em.GetListenerCount("tick"); n != 0 {
+		t.Errorf("All waiters should be removed, got %d listeners", n)
+	}
+}
+
+// TestWaitForAny 测试等待多个事件中最先发生的一个
+func TestWaitForAny(t *testing.T) {
+	em := NewEmitter[string, int]()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		em.EmitSync("b", 2)
+		em.EmitSync("a", 1)
+	}()
+
+	msg, err := em.WaitForAny(ctx, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Event != "b" || !slices.Equal(msg.Args, []int{2}) {
+		t.Errorf("Expected first event b [2], got %v %v", msg.Event, msg.Args)
+	}
+	waitUntil(t, func() bool { return em.GetListenerCount("a") == 0 && em.GetListenerCount("b") == 0 })
+
+	timeout, cancelTimeout := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelTimeout()
+	if _, err := em.WaitForAny(timeout, []string{"a"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestWaitForAll 测试等待每个事件都至少触发一次
+func TestWaitForAll(t *testing.T) {
+	em := NewEmitter[string, int]()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		em.EmitSync("db", 1)
+		em.EmitSync("db", 2)
+		em.Emit("cache", 3)
+	}()
+
+	got, err := em.WaitForAll(ctx, []string{"db", "cache", "db"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 2 || !slices.Equal(got["db"], []int{1}) || !slices.Equal(got["cache"], []int{3}) {
+		t.Errorf("Expected first args of each event, got %v", got)
+	}
+
+	timeout, cancelTimeout := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelTimeout()
+	go em.EmitSync("db", 4)
+	if _, err := em.WaitForAll(timeout, []string{"db", "never"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if em.GetListenerCount("db") != 0 || em.GetListenerCount("never") != 0 {
+		t.Errorf("Listeners should be removed after timeout")
+	}
+}
@@ -1,6 +1,9 @@
 package generator
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 // Yield 用于在生成器中产生值并接收返回值
 type Yield[T any] struct {
@@ -22,10 +25,10 @@ func (y *Yield[T]) Yield(value T) any {
 
 // Generator 是一个泛型生成器，支持迭代生成值
 type Generator[T any] struct {
-	yield     Yield[T]  // 用于值传递的 Yield 实例
-	doneChan  chan bool // 标记生成器是否完成
-	isDone    bool      // 内部状态，标记是否已完成
-	closeOnce sync.Once // 确保通道只关闭一次
+	yield     Yield[T]    // 用于值传递的 Yield 实例
+	doneChan  chan bool   // 标记生成器是否完成
+	isDone    atomic.Bool // 内部状态，标记是否已完成，可能被生成协程与调用方同时访问
+	closeOnce sync.Once   // 确保通道只关闭一次
 }
 
 // NewGenerator 创建并启动一个新的生成器
@@ -52,7 +55,7 @@ func (g *Generator[T]) close() {
 		close(g.yield.valueChan)
 		close(g.yield.resultChan)
 		close(g.doneChan)
-		g.isDone = true
+		g.isDone.Store(true)
 	})
 }
 
@@ -60,7 +63,7 @@ func (g *Generator[T]) close() {
 // values 可选参数，用于向生成器传递返回值
 // 返回值：生成的 value 和 done 状态（true 表示生成结束）
 func (g *Generator[T]) Next(values ...any) (value T, done bool) {
-	if g.isDone {
+	if g.isDone.Load() {
 		return value, true // 如果已完成，直接返回
 	}
 
@@ -68,7 +71,7 @@ func (g *Generator[T]) Next(values ...any) (value T, done bool) {
 	select {
 	case val, ok := <-g.yield.valueChan:
 		if !ok {
-			g.isDone = true
+			g.isDone.Store(true)
 			return value, true // 通道关闭，表示生成结束
 		}
 		// 发送返回值（如果提供）或 nil
@@ -79,12 +82,12 @@ func (g *Generator[T]) Next(values ...any) (value T, done bool) {
 		select {
 		case g.yield.resultChan <- result:
 		case <-g.doneChan:
-			g.isDone = true
+			g.isDone.Store(true)
 			return value, true
 		}
 		return val, false
 	case <-g.doneChan:
-		g.isDone = true
+		g.isDone.Store(true)
 		return value, true // 生成器完成
 	}
 }
@@ -15,7 +15,7 @@ func TestNewGenerator(t *testing.T) {
 	})
 
 	// 检查初始状态
-	if gen.isDone {
+	if gen.isDone.Load() {
 		t.Error("Newly created generator should not be done")
 	}
 	if gen.doneChan == nil || gen.yield.valueChan == nil || gen.yield.resultChan == nil {
@@ -123,7 +123,7 @@ func TestGeneratorDone(t *testing.T) {
 	if !done {
 		t.Error("Next should return done=true after generator completes")
 	}
-	if !gen.isDone {
+	if !gen.isDone.Load() {
 		t.Error("Generator should be marked as done")
 	}
 
@@ -151,7 +151,7 @@ func TestEmptyGenerator(t *testing.T) {
 	if value != 0 {
 		t.Errorf("Expected zero value for empty generator, got %d", value)
 	}
-	if !gen.isDone {
+	if !gen.isDone.Load() {
 		t.Error("Empty generator should be marked as done")
 	}
 }
@@ -182,7 +182,7 @@ func TestConcurrentSafety(t *testing.T) {
 	}
 
 	wg.Wait()
-	if !gen.isDone {
+	if !gen.isDone.Load() {
 		t.Error("Generator should be marked as done after concurrent access")
 	}
 }
@@ -0,0 +1,160 @@
+package workerpool
+
+import (
+	"sync"
+	"time"
+)
+
+// BatcherOption 定义 Batcher 的可选配置函数。
+type BatcherOption[T any] func(*Batcher[T])
+
+// WithBatchErrorHandler 设置批处理失败时的回调，batch 为失败的批次。
+// 协程池已停止导致批次无法提交，或批次在执行前被协程池丢弃时，回调收到 ErrStopped。
+// 回调在工作协程或调用 Add、Flush、Stop 的协程中执行，需保证并发安全。
+func WithBatchErrorHandler[T any](fn func(batch []T, err error)) BatcherOption[T] {
+	return func(b *Batcher[T]) {
+		b.onError = fn
+	}
+}
+
+// Batcher 将逐个到达的元素聚合为批次，并在协程池中执行批处理函数。
+//
+// 当批次达到 maxSize 个元素，或批次中第一个元素等待超过 maxDelay 时，
+// 批次被提交到协程池执行。多个批次可能并行执行，执行顺序不保证与提交顺序一致。
+type Batcher[T any] struct {
+	pool     *WorkerPool
+	handler  func(batch []T) error
+	onError  func(batch []T, err error)
+	maxSize  int
+	maxDelay time.Duration
+
+	mu      sync.Mutex
+	items   []T
+	timer   *time.Timer
+	gen     uint64 // 批次编号，用于识别过期的计时器
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewBatcher 创建一个在 pool 上执行 handler 的批处理器。
+//
+// maxSize 为批次的最大元素数，小于 1 时按 1 处理；maxDelay 为批次的最大等待时间，
+// <= 0 表示只按数量聚合，未满的批次需通过 Flush 或 Stop 提交。
+func NewBatcher[T any](pool *WorkerPool, maxSize int, maxDelay time.Duration, handler func(batch []T) error, opts ...BatcherOption[T]) *Batcher[T] {
+	b := &Batcher[T]{
+		pool:     pool,
+		handler:  handler,
+		maxSize:  max(maxSize, 1),
+		maxDelay: maxDelay,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Add 将元素加入当前批次，批次已满时立即提交到协程池。
+// 批处理器停止后返回 ErrStopped。
+func (b *Batcher[T]) Add(item T) error {
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return ErrStopped
+	}
+	b.items = append(b.items, item)
+	var batch []T
+	if len(b.items) >= b.maxSize {
+		batch = b.take()
+	} else if len(b.items) == 1 && b.maxDelay > 0 {
+		gen := b.gen
+		b.timer = time.AfterFunc(b.maxDelay, func() { b.expire(gen) })
+	}
+	b.mu.Unlock()
+
+	if batch != nil {
+		b.submit(batch)
+	}
+	return nil
+}
+
+// Flush 立即提交当前未满的批次，不等待其执行完成。
+func (b *Batcher[T]) Flush() {
+	b.mu.Lock()
+	batch := b.take()
+	b.mu.Unlock()
+	if batch != nil {
+		b.submit(batch)
+	}
+}
+
+// Stop 停止批处理器，提交剩余的元素并等待所有批次执行完成。
+// 停止后 Add 返回 ErrStopped。Stop 不会停止协程池，应在停止协程池之前调用。
+func (b *Batcher[T]) Stop() {
+	b.mu.Lock()
+	b.stopped = true
+	batch := b.take()
+	b.mu.Unlock()
+	if batch != nil {
+		b.submit(batch)
+	}
+	b.wg.Wait()
+}
+
+// take 在持有锁的情况下取出当前批次并开始新的批次，当前批次为空时返回 nil。
+// 取出的批次必须通过 submit 提交。
+func (b *Batcher[T]) take() []T {
+	if len(b.items) == 0 {
+		return nil
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.items
+	b.items = nil
+	b.gen++
+	// 在锁内登记批次，确保 Stop 等待所有已取出的批次
+	b.wg.Add(1)
+	return batch
+}
+
+// expire 在批次等待超过 maxDelay 后提交批次，gen 不匹配表示该批次已被提交。
+func (b *Batcher[T]) expire(gen uint64) {
+	b.mu.Lock()
+	if b.gen != gen {
+		b.mu.Unlock()
+		return
+	}
+	batch := b.take()
+	b.mu.Unlock()
+	if batch != nil {
+		b.submit(batch)
+	}
+}
+
+// submit 将批次提交到协程池执行，失败时回调错误处理函数。
+// 批次在执行前被协程池丢弃（Stop 或 Shutdown 超时）时同样以 ErrStopped 回调。
+func (b *Batcher[T]) submit(batch []T) {
+	stopped := func() {
+		b.report(batch, ErrStopped)
+		b.wg.Done()
+	}
+	ok := b.pool.trySubmit(newTaskWithDiscard(func() error {
+		defer b.wg.Done()
+		err := b.handler(batch)
+		if err != nil {
+			b.report(batch, err)
+		}
+		return err
+	}, stopped))
+	if !ok {
+		stopped()
+	}
+}
+
+// report 回调错误处理函数。
+func (b *Batcher[T]) report(batch []T, err error) {
+	if b.onError != nil {
+		b.onError(batch, err)
+	}
+}
@@ -0,0 +1,188 @@
+package workerpool
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestBatcherSize 测试按数量聚合批次
+func TestBatcherSize(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+
+	var mu sync.Mutex
+	var sizes []int
+	var total int32
+	b := NewBatcher(pool, 3, 0, func(batch []int) error {
+		mu.Lock()
+		sizes = append(sizes, len(batch))
+		mu.Unlock()
+		atomic.AddInt32(&total, int32(len(batch)))
+		return nil
+	})
+	for i := 0; i < 7; i++ {
+		if err := b.Add(i); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&total) == 6 })
+
+	// 未满的批次在 Stop 时提交
+	b.Stop()
+	if total != 7 {
+		t.Errorf("Expected 7 items, got %d", total)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sizes) != 3 || sizes[0] != 3 || sizes[1] != 3 || sizes[2] != 1 {
+		t.Errorf("Expected batch sizes [3 3 1], got %v", sizes)
+	}
+}
+
+// TestBatcherDelay 测试批次等待超时后提交
+func TestBatcherDelay(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	done := make(chan []string, 1)
+	b := NewBatcher(pool, 100, 20*time.Millisecond, func(batch []string) error {
+		done <- batch
+		return nil
+	})
+	defer b.Stop()
+
+	start := time.Now()
+	b.Add("a")
+	b.Add("b")
+	select {
+	case batch := <-done:
+		if len(batch) != 2 {
+			t.Errorf("Expected batch [a b], got %v", batch)
+		}
+		if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+			t.Errorf("Batch flushed too early after %v", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Batch should be flushed after max delay")
+	}
+}
+
+// TestBatcherFlush 测试手动提交未满的批次
+func TestBatcherFlush(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	done := make(chan int, 1)
+	b := NewBatcher(pool, 10, time.Hour, func(batch []int) error {
+		done <- len(batch)
+		return nil
+	})
+	defer b.Stop()
+
+	b.Add(1)
+	b.Flush()
+	if n := <-done; n != 1 {
+		t.Errorf("Expected batch of 1, got %d", n)
+	}
+	b.Flush() // 空批次不提交
+}
+
+// TestBatcherError 测试批处理失败时按批次回调错误
+func TestBatcherError(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+
+	errBad := errors.New("bad batch")
+	var mu sync.Mutex
+	var failed [][]int
+	b := NewBatcher(pool, 2, 0, func(batch []int) error {
+		if batch[0] == 2 {
+			return errBad
+		}
+		return nil
+	}, WithBatchErrorHandler(func(batch []int, err error) {
+		if !errors.Is(err, errBad) {
+			t.Errorf("Expected errBad, got %v", err)
+		}
+		mu.Lock()
+		failed = append(failed, batch)
+		mu.Unlock()
+	}))
+	for i := 0; i < 6; i++ {
+		b.Add(i)
+	}
+	b.Stop()
+
+	if len(failed) != 1 || failed[0][0] != 2 || failed[0][1] != 3 {
+		t.Errorf("Expected failed batch [2 3], got %v", failed)
+	}
+	if s := pool.Stats(); s.Failed != 1 || s.Completed != 2 {
+		t.Errorf("Expected 1 failed and 2 completed batches, got %+v", s)
+	}
+}
+
+// TestBatcherStopped 测试停止后拒绝新元素以及协程池停止时的错误回调
+func TestBatcherStopped(t *testing.T) {
+	pool := New(1)
+	var reported error
+	b := NewBatcher(pool, 10, 0, func([]int) error { return nil },
+		WithBatchErrorHandler(func(_ []int, err error) { reported = err }))
+
+	b.Add(1)
+	pool.Stop()
+	b.Stop()
+	if !errors.Is(reported, ErrStopped) {
+		t.Errorf("Expected ErrStopped when pool is stopped, got %v", reported)
+	}
+	if err := b.Add(2); !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped after Stop, got %v", err)
+	}
+}
+
+// TestBatcherDiscarded 测试已提交的批次被协程池丢弃时回调 ErrStopped 且 Stop 不会阻塞
+func TestBatcherDiscarded(t *testing.T) {
+	pool := New(1)
+	release := make(chan struct{})
+	pool.Submit(func() { <-release })
+
+	var mu sync.Mutex
+	var failed [][]int
+	b := NewBatcher(pool, 1, 0, func(batch []int) error { return nil },
+		WithBatchErrorHandler(func(batch []int, err error) {
+			if errors.Is(err, ErrStopped) {
+				mu.Lock()
+				failed = append(failed, batch)
+				mu.Unlock()
+			}
+		}))
+	b.Add(1)
+	waitFor(t, func() bool { return pool.WaitingQueueSize() == 1 })
+
+	stopped := make(chan struct{})
+	go func() {
+		pool.Stop()
+		close(stopped)
+	}()
+	waitFor(t, func() bool { return pool.Stats().Discarded == 1 })
+	close(release)
+	<-stopped
+
+	done := make(chan struct{})
+	go func() {
+		b.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop should not wait for a discarded batch")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failed) != 1 || len(failed[0]) != 1 || failed[0][0] != 1 {
+		t.Errorf("Discarded batch should be reported with ErrStopped, got %v", failed)
+	}
+}
@@ -0,0 +1,186 @@
+package workerpool
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultAdaptInterval 是自适应并发控制的默认采样周期。
+const DefaultAdaptInterval = time.Second
+
+// Sample 描述一个采样周期内观测到的协程池运行指标，作为 Controller 的输入。
+type Sample struct {
+	Limit      int           // 当前最大并发工作协程数
+	InFlight   int           // 采样时正在执行的任务数
+	Queued     int           // 采样时等待队列中的任务数
+	Completed  int           // 本周期内完成的任务数
+	Throughput float64       // 本周期内每秒完成的任务数
+	AvgLatency time.Duration // 本周期内任务的平均执行耗时，无完成任务时为 0
+	Interval   time.Duration // 本周期的实际时长
+}
+
+// Controller 定义自适应并发控制器接口。
+//
+// 协程池在每个采样周期结束时调用 Update，并将返回值截断到
+// [常驻工作协程数, New 时指定的 maxWorkers] 区间后作为新的并发上限。
+// Update 只会在单个协程中被串行调用，实现无需自行加锁。
+type Controller interface {
+	Update(s Sample) int
+}
+
+// WithAdaptiveConcurrency 启用自适应并发控制。
+//
+// 协程池每隔 interval 采集一次任务延迟与吞吐量，交由 c 计算新的并发上限。
+// 并发上限不会低于 WithMinWorkers 指定的常驻数量（至少为 1），
+// 也不会超过 New 时指定的 maxWorkers。若 interval <= 0，将使用 DefaultAdaptInterval。
+func WithAdaptiveConcurrency(c Controller, interval time.Duration) Option {
+	return func(p *WorkerPool) {
+		if interval <= 0 {
+			interval = DefaultAdaptInterval
+		}
+		p.controller = c
+		p.adaptEvery = interval
+	}
+}
+
+// adapt 是自适应并发控制的主循环，按采样周期调用控制器并调整并发上限。
+func (p *WorkerPool) adapt() {
+	ticker := time.NewTicker(p.adaptEvery)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-p.stopSignal:
+			return
+		case now := <-ticker.C:
+			count, total := p.latency.reset()
+			s := Sample{
+				Limit:     p.Size(),
+				InFlight:  int(p.running.Load()),
+				Queued:    p.WaitingQueueSize(),
+				Completed: int(count),
+				Interval:  now.Sub(last),
+			}
+			if count > 0 {
+				s.AvgLatency = total / time.Duration(count)
+			}
+			if s.Interval > 0 {
+				s.Throughput = float64(count) / s.Interval.Seconds()
+			}
+			last = now
+
+			limit := min(max(p.controller.Update(s), p.minWorkers, 1), p.ceiling)
+			p.Resize(limit)
+		}
+	}
+}
+
+// latencyWindow 累计一个采样周期内的任务执行耗时，可被多个工作协程并发写入。
+type latencyWindow struct {
+	count atomic.Int64
+	total atomic.Int64
+}
+
+// record 记录一次任务执行耗时。
+func (w *latencyWindow) record(d time.Duration) {
+	w.count.Add(1)
+	w.total.Add(int64(d))
+}
+
+// reset 返回当前周期的完成数与总耗时，并开始新的周期。
+func (w *latencyWindow) reset() (int64, time.Duration) {
+	return w.count.Swap(0), time.Duration(w.total.Swap(0))
+}
+
+// AIMD 是基于加性增、乘性减（Additive Increase Multiplicative Decrease）的并发控制器。
+//
+// 当平均执行耗时超过 LatencyThreshold 时，并发上限按 Backoff 比例缩减；
+// 否则在并发上限被充分利用（正在执行的任务数不少于上限的一半）时加 1。
+type AIMD struct {
+	LatencyThreshold time.Duration // 判定过载的平均耗时阈值
+	Backoff          float64       // 过载时的缩减比例，取值 (0, 1)，默认 0.9
+}
+
+// NewAIMD 创建一个 AIMD 控制器，threshold 为判定过载的平均耗时阈值。
+func NewAIMD(threshold time.Duration) *AIMD {
+	return &AIMD{LatencyThreshold: threshold, Backoff: 0.9}
+}
+
+// Update 实现 Controller 接口。
+func (a *AIMD) Update(s Sample) int {
+	if s.Completed == 0 {
+		return s.Limit
+	}
+	if s.AvgLatency > a.LatencyThreshold {
+		backoff := a.Backoff
+		if backoff <= 0 || backoff >= 1 {
+			backoff = 0.9
+		}
+		return int(float64(s.Limit) * backoff)
+	}
+	if s.InFlight*2 >= s.Limit {
+		return s.Limit + 1
+	}
+	return s.Limit
+}
+
+// Gradient 是基于延迟梯度的并发控制器，参考 Netflix concurrency-limits 的 Gradient2 算法。
+//
+// 控制器维护长期平均延迟，并与当前周期的平均延迟比较：当前延迟升高时，
+// 梯度小于 1，并发上限随之下降；延迟稳定时，并发上限以 sqrt(limit) 为步长缓慢增长。
+// Gradient 带有内部状态，不能在多个协程池之间共享。
+type Gradient struct {
+	Tolerance float64 // 可容忍的延迟放大倍数，默认 1.5
+	Smoothing float64 // 新上限的平滑系数，取值 (0, 1]，默认 0.2
+	Window    int     // 长期平均延迟的指数平滑窗口，默认 600
+
+	longRTT float64
+	limit   float64
+}
+
+// NewGradient 创建一个使用默认参数的 Gradient 控制器。
+func NewGradient() *Gradient {
+	return &Gradient{Tolerance: 1.5, Smoothing: 0.2, Window: 600}
+}
+
+// Update 实现 Controller 接口。
+func (g *Gradient) Update(s Sample) int {
+	if g.limit == 0 || int(g.limit) != s.Limit {
+		g.limit = float64(s.Limit)
+	}
+	if s.Completed == 0 || s.AvgLatency <= 0 {
+		return s.Limit
+	}
+
+	shortRTT := float64(s.AvgLatency)
+	if g.longRTT == 0 {
+		g.longRTT = shortRTT
+	} else {
+		window := float64(max(g.Window, 1))
+		g.longRTT += (shortRTT - g.longRTT) / window
+	}
+	// 长期延迟明显高于当前延迟时快速回落，以便从过载中恢复
+	if g.longRTT/shortRTT > 2 {
+		g.longRTT *= 0.95
+	}
+
+	tolerance := g.Tolerance
+	if tolerance <= 0 {
+		tolerance = 1.5
+	}
+	smoothing := g.Smoothing
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = 0.2
+	}
+
+	gradient := math.Max(0.5, math.Min(1, tolerance*g.longRTT/shortRTT))
+	next := g.limit*gradient + math.Sqrt(g.limit)
+	// 并发上限未被充分利用时不增长，避免上限无限膨胀
+	if next > g.limit && s.InFlight*2 < s.Limit {
+		return s.Limit
+	}
+	g.limit = g.limit*(1-smoothing) + next*smoothing
+	return int(g.limit)
+}
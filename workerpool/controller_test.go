@@ -0,0 +1,116 @@
+package workerpool
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fixedController 总是返回固定并发上限的控制器，用于测试
+type fixedController struct {
+	limit   int
+	samples atomic.Int32
+}
+
+func (c *fixedController) Update(s Sample) int {
+	c.samples.Add(1)
+	return c.limit
+}
+
+// TestAdaptiveConcurrency 测试控制器输出被应用并截断到上下限
+func TestAdaptiveConcurrency(t *testing.T) {
+	c := &fixedController{limit: 100}
+	pool := New(8, WithMinWorkers(2), WithAdaptiveConcurrency(c, 10*time.Millisecond))
+	defer pool.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	if c.samples.Load() == 0 {
+		t.Fatal("Controller should be sampled periodically")
+	}
+	if pool.Size() != 8 {
+		t.Errorf("Limit should be capped at max workers 8, got %d", pool.Size())
+	}
+
+	c2 := &fixedController{limit: 0}
+	pool2 := New(8, WithMinWorkers(2), WithAdaptiveConcurrency(c2, 10*time.Millisecond))
+	defer pool2.Stop()
+	time.Sleep(50 * time.Millisecond)
+	if pool2.Size() != 2 {
+		t.Errorf("Limit should be floored at min workers 2, got %d", pool2.Size())
+	}
+}
+
+// TestAdaptiveConcurrencySample 测试采样数据包含延迟与吞吐量
+func TestAdaptiveConcurrencySample(t *testing.T) {
+	samples := make(chan Sample, 16)
+	c := controllerFunc(func(s Sample) int {
+		select {
+		case samples <- s:
+		default:
+		}
+		return s.Limit
+	})
+	pool := New(2, WithAdaptiveConcurrency(c, 50*time.Millisecond))
+	defer pool.Stop()
+
+	for i := 0; i < 4; i++ {
+		pool.Submit(func() { time.Sleep(10 * time.Millisecond) })
+	}
+
+	s := <-samples
+	if s.Completed != 4 {
+		t.Errorf("Expected 4 completed tasks in sample, got %d", s.Completed)
+	}
+	if s.AvgLatency < 10*time.Millisecond {
+		t.Errorf("Expected average latency >= 10ms, got %v", s.AvgLatency)
+	}
+	if s.Throughput <= 0 {
+		t.Errorf("Expected positive throughput, got %f", s.Throughput)
+	}
+	if s.Limit != 2 {
+		t.Errorf("Expected sample limit 2, got %d", s.Limit)
+	}
+}
+
+// controllerFunc 将函数适配为 Controller
+type controllerFunc func(s Sample) int
+
+func (f controllerFunc) Update(s Sample) int { return f(s) }
+
+// TestAIMD 测试 AIMD 控制器的增减策略
+func TestAIMD(t *testing.T) {
+	a := NewAIMD(100 * time.Millisecond)
+
+	if n := a.Update(Sample{Limit: 10, InFlight: 8, Completed: 5, AvgLatency: 10 * time.Millisecond}); n != 11 {
+		t.Errorf("AIMD should increase by 1 under low latency, got %d", n)
+	}
+	if n := a.Update(Sample{Limit: 10, InFlight: 2, Completed: 5, AvgLatency: 10 * time.Millisecond}); n != 10 {
+		t.Errorf("AIMD should not grow an underutilized limit, got %d", n)
+	}
+	if n := a.Update(Sample{Limit: 10, InFlight: 10, Completed: 5, AvgLatency: time.Second}); n != 9 {
+		t.Errorf("AIMD should back off under high latency, got %d", n)
+	}
+	if n := a.Update(Sample{Limit: 10}); n != 10 {
+		t.Errorf("AIMD should keep limit without completed tasks, got %d", n)
+	}
+}
+
+// TestGradient 测试梯度控制器随延迟升降调整上限
+func TestGradient(t *testing.T) {
+	g := NewGradient()
+	limit := 10
+	for i := 0; i < 20; i++ {
+		limit = g.Update(Sample{Limit: limit, InFlight: limit, Completed: 10, AvgLatency: 10 * time.Millisecond})
+	}
+	if limit <= 10 {
+		t.Errorf("Gradient should grow limit under stable latency, got %d", limit)
+	}
+
+	grown := limit
+	for i := 0; i < 20; i++ {
+		limit = g.Update(Sample{Limit: limit, InFlight: limit, Completed: 10, AvgLatency: 100 * time.Millisecond})
+	}
+	if limit >= grown {
+		t.Errorf("Gradient should shrink limit when latency rises, got %d (was %d)", limit, grown)
+	}
+}
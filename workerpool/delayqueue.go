@@ -0,0 +1,102 @@
+package workerpool
+
+import (
+	"container/heap"
+	"time"
+)
+
+// delayQueue 是按就绪时间排序的延迟任务最小堆，仅由 dispatch 协程访问。
+// 堆顶任务的就绪时间通过内部计时器通知，堆为空时 C 返回 nil 通道。
+type delayQueue struct {
+	items delayHeap
+	timer *time.Timer
+	seq   uint64
+}
+
+// delayedTask 是延迟队列中的元素，seq 保证相同就绪时间的任务按入队顺序出队。
+type delayedTask struct {
+	task  *task
+	ready time.Time
+	seq   uint64
+}
+
+// Len 返回延迟队列中的任务数量。
+func (q *delayQueue) Len() int {
+	return len(q.items)
+}
+
+// C 返回堆顶任务就绪时触发的通道，延迟队列为空时返回 nil。
+func (q *delayQueue) C() <-chan time.Time {
+	if len(q.items) == 0 {
+		return nil
+	}
+	return q.timer.C
+}
+
+// push 将任务加入延迟队列，在 ready 时刻就绪。
+func (q *delayQueue) push(t *task, ready time.Time) {
+	q.seq++
+	heap.Push(&q.items, delayedTask{task: t, ready: ready, seq: q.seq})
+	q.rearm()
+}
+
+// popReady 弹出所有已就绪的任务，按就绪时间先后排列。
+func (q *delayQueue) popReady(now time.Time) []*task {
+	var ready []*task
+	for len(q.items) > 0 && !q.items[0].ready.After(now) {
+		ready = append(ready, heap.Pop(&q.items).(delayedTask).task)
+	}
+	q.rearm()
+	return ready
+}
+
+// drain 清空延迟队列并返回其中的全部任务。
+func (q *delayQueue) drain() []*task {
+	tasks := make([]*task, 0, len(q.items))
+	for len(q.items) > 0 {
+		tasks = append(tasks, heap.Pop(&q.items).(delayedTask).task)
+	}
+	q.rearm()
+	return tasks
+}
+
+// rearm 将计时器重置为堆顶任务的就绪时间。
+func (q *delayQueue) rearm() {
+	if q.timer == nil {
+		q.timer = time.NewTimer(time.Hour)
+	}
+	if !q.timer.Stop() {
+		select {
+		case <-q.timer.C:
+		default:
+		}
+	}
+	if len(q.items) > 0 {
+		q.timer.Reset(time.Until(q.items[0].ready))
+	}
+}
+
+// delayHeap 实现 heap.Interface。
+type delayHeap []delayedTask
+
+func (h delayHeap) Len() int { return len(h) }
+
+func (h delayHeap) Less(i, j int) bool {
+	if h[i].ready.Equal(h[j].ready) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].ready.Before(h[j].ready)
+}
+
+func (h delayHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *delayHeap) Push(x any) { *h = append(*h, x.(delayedTask)) }
+
+func (h *delayHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = delayedTask{}
+	*h = old[:n-1]
+	return item
+}
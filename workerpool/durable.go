@@ -0,0 +1,180 @@
+package workerpool
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/wsshow/op/deque"
+)
+
+// ErrUnknownTask 表示提交或恢复的命名任务没有注册对应的处理函数。
+var ErrUnknownTask = errors.New("workerpool: unknown task name")
+
+// TaskRecord 是可持久化的命名任务记录。
+type TaskRecord struct {
+	ID      uint64 // 由 QueueStore 分配的唯一标识
+	Name    string // 任务处理函数的注册名
+	Payload []byte // 传递给处理函数的参数
+}
+
+// QueueStore 定义命名任务的存储接口。
+//
+// 命名任务在提交时写入存储，执行结束后被确认；进程重启后，未确认的任务可通过
+// WorkerPool.Recover 重新提交，从而实现至少一次（at-least-once）的执行语义。
+// 实现必须保证并发安全。
+type QueueStore interface {
+	// Append 持久化一条任务记录并返回分配的 ID。
+	Append(name string, payload []byte) (uint64, error)
+	// Ack 确认任务已执行完毕，之后不再出现在 Pending 中。
+	Ack(id uint64) error
+	// Pending 按提交顺序返回所有未确认的任务记录。
+	Pending() ([]TaskRecord, error)
+	// Close 关闭存储并释放资源。
+	Close() error
+}
+
+// TaskHandler 是命名任务的处理函数，payload 为提交时传入的参数。
+type TaskHandler func(payload []byte) error
+
+// WithQueueStore 设置命名任务的存储，默认使用 NewMemoryStore 创建的内存存储。
+// 协程池不负责关闭存储，调用方应在停止协程池后自行调用 Close。
+func WithQueueStore(store QueueStore) Option {
+	return func(p *WorkerPool) {
+		if store != nil {
+			p.store = store
+		}
+	}
+}
+
+// Register 注册命名任务的处理函数，同名注册将覆盖之前的处理函数。
+// 应在 SubmitNamed 和 Recover 之前完成注册。
+func (p *WorkerPool) Register(name string, handler TaskHandler) {
+	p.handlersMu.Lock()
+	defer p.handlersMu.Unlock()
+	if p.handlers == nil {
+		p.handlers = make(map[string]TaskHandler)
+	}
+	p.handlers[name] = handler
+}
+
+// SubmitNamed 提交一个命名任务，任务记录先写入存储再进入等待队列。
+//
+// 任务执行结束（无论成功或失败）后才会在存储中确认，因此进程在执行完成前崩溃时，
+// 该任务会在重启后通过 Recover 再次执行，处理函数应保证幂等。
+// 未注册 name 时返回 ErrUnknownTask，写入存储失败时返回相应错误。
+func (p *WorkerPool) SubmitNamed(name string, payload []byte) error {
+	handler, ok := p.handler(name)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownTask, name)
+	}
+	id, err := p.store.Append(name, payload)
+	if err != nil {
+		return err
+	}
+	p.submit(p.namedTask(TaskRecord{ID: id, Name: name, Payload: payload}, handler))
+	return nil
+}
+
+// Recover 将存储中所有未确认的命名任务按提交顺序重新提交到协程池，返回提交的任务数。
+//
+// 应在进程启动、完成 Register 之后调用一次；重复调用会导致任务被重复提交。
+// 没有注册处理函数的任务将被跳过并保留在存储中，返回的错误中包含 ErrUnknownTask。
+func (p *WorkerPool) Recover() (int, error) {
+	records, err := p.store.Pending()
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	n := 0
+	for _, r := range records {
+		handler, ok := p.handler(r.Name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %q (id %d)", ErrUnknownTask, r.Name, r.ID))
+			continue
+		}
+		p.submit(p.namedTask(r, handler))
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+// handler 返回 name 对应的处理函数。
+func (p *WorkerPool) handler(name string) (TaskHandler, bool) {
+	p.handlersMu.RLock()
+	defer p.handlersMu.RUnlock()
+	h, ok := p.handlers[name]
+	return h, ok
+}
+
+// namedTask 创建执行命名任务并在结束后确认记录的内部任务。
+func (p *WorkerPool) namedTask(r TaskRecord, handler TaskHandler) *task {
+	t := newTask(func() error {
+		err := handler(r.Payload)
+		if ackErr := p.store.Ack(r.ID); ackErr != nil {
+			return errors.Join(err, ackErr)
+		}
+		return err
+	})
+	t.record = &r
+	return t
+}
+
+// MemoryStore 是基于 deque.Deque 的内存 QueueStore 实现，也是协程池的默认存储。
+// 记录仅保存在内存中，不能跨进程重启保留。
+type MemoryStore struct {
+	mu      sync.Mutex
+	nextID  uint64
+	records deque.Deque[TaskRecord]
+	acked   map[uint64]struct{}
+}
+
+// NewMemoryStore 创建一个内存存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{acked: make(map[uint64]struct{})}
+}
+
+// Append 实现 QueueStore 接口。
+func (s *MemoryStore) Append(name string, payload []byte) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	s.records.PushBack(TaskRecord{ID: s.nextID, Name: name, Payload: payload})
+	return s.nextID, nil
+}
+
+// Ack 实现 QueueStore 接口。
+// 记录在其之前的记录全部确认后才从队列中移除，以保持 Pending 的提交顺序。
+func (s *MemoryStore) Ack(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acked[id] = struct{}{}
+	for s.records.Size() > 0 {
+		front := s.records.Front().ID
+		if _, ok := s.acked[front]; !ok {
+			break
+		}
+		delete(s.acked, front)
+		s.records.PopFront()
+	}
+	return nil
+}
+
+// Pending 实现 QueueStore 接口。
+func (s *MemoryStore) Pending() ([]TaskRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]TaskRecord, 0, s.records.Size())
+	for i := 0; i < s.records.Size(); i++ {
+		r := s.records.At(i)
+		if _, ok := s.acked[r.ID]; !ok {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// Close 实现 QueueStore 接口。
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,111 @@
+package workerpool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// TestMemoryStore 测试内存存储的追加、乱序确认与未确认记录查询
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	for _, name := range []string{"a", "b", "c"} {
+		if _, err := s.Append(name, []byte(name)); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	s.Ack(2)
+	records, _ := s.Pending()
+	if len(records) != 2 || records[0].Name != "a" || records[1].Name != "c" {
+		t.Errorf("Expected pending [a c], got %+v", records)
+	}
+
+	s.Ack(1)
+	s.Ack(3)
+	records, _ = s.Pending()
+	if len(records) != 0 {
+		t.Errorf("Expected no pending records, got %+v", records)
+	}
+	if s.records.Size() != 0 || len(s.acked) != 0 {
+		t.Errorf("Expected acked records to be released, got %d records and %d acks", s.records.Size(), len(s.acked))
+	}
+}
+
+// TestSubmitNamed 测试命名任务的执行与确认
+func TestSubmitNamed(t *testing.T) {
+	store := NewMemoryStore()
+	pool := New(2, WithQueueStore(store))
+
+	var mu sync.Mutex
+	var got []string
+	pool.Register("echo", func(payload []byte) error {
+		mu.Lock()
+		got = append(got, string(payload))
+		mu.Unlock()
+		return nil
+	})
+
+	for _, s := range []string{"x", "y", "z"} {
+		if err := pool.SubmitNamed("echo", []byte(s)); err != nil {
+			t.Fatalf("SubmitNamed failed: %v", err)
+		}
+	}
+	pool.StopWait()
+
+	if len(got) != 3 {
+		t.Errorf("Expected 3 executions, got %v", got)
+	}
+	if records, _ := store.Pending(); len(records) != 0 {
+		t.Errorf("Expected all records to be acked, got %+v", records)
+	}
+}
+
+// TestSubmitNamedUnknown 测试提交未注册的命名任务
+func TestSubmitNamedUnknown(t *testing.T) {
+	store := NewMemoryStore()
+	pool := New(1, WithQueueStore(store))
+	defer pool.Stop()
+
+	if err := pool.SubmitNamed("missing", nil); !errors.Is(err, ErrUnknownTask) {
+		t.Errorf("Expected ErrUnknownTask, got %v", err)
+	}
+	if records, _ := store.Pending(); len(records) != 0 {
+		t.Errorf("Unknown task should not be stored, got %+v", records)
+	}
+}
+
+// TestRecover 测试重新提交存储中未确认的任务
+func TestRecover(t *testing.T) {
+	store := NewMemoryStore()
+	store.Append("job", []byte("1"))
+	store.Append("other", []byte("2"))
+	store.Append("job", []byte("3"))
+
+	pool := New(1, WithQueueStore(store))
+	var mu sync.Mutex
+	var got []string
+	pool.Register("job", func(payload []byte) error {
+		mu.Lock()
+		got = append(got, string(payload))
+		mu.Unlock()
+		return nil
+	})
+
+	n, err := pool.Recover()
+	if n != 2 {
+		t.Errorf("Expected 2 recovered tasks, got %d", n)
+	}
+	if !errors.Is(err, ErrUnknownTask) {
+		t.Errorf("Expected ErrUnknownTask for unregistered record, got %v", err)
+	}
+	pool.StopWait()
+
+	if len(got) != 2 || got[0] != "1" || got[1] != "3" {
+		t.Errorf("Expected [1 3] in order, got %v", got)
+	}
+	records, _ := store.Pending()
+	if len(records) != 1 || records[0].Name != "other" {
+		t.Errorf("Expected unregistered record to remain, got %+v", records)
+	}
+}
@@ -0,0 +1,162 @@
+package workerpool
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+
+	"github.com/wsshow/op/deque"
+)
+
+// Group 是绑定到 WorkerPool 的一组相关任务，语义类似 errgroup.Group。
+//
+// 组内任务在所属协程池中执行，可单独等待整组完成。默认情况下任一任务
+// 返回错误后组的 context 将被取消，尚未开始的组内任务不再执行（fail-fast）。
+// 通过 SetLimit 可限制组内任务的并发数，超出限制的任务在组内排队，
+// 不会占用协程池的等待队列，也不会阻塞同一协程池中的其他任务。
+type Group struct {
+	pool     *WorkerPool
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	limit    int
+	failFast bool
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	active  int
+	pending deque.Deque[func(ctx context.Context) error]
+	errs    []error
+}
+
+// NewGroup 创建一个绑定到协程池的任务组，并返回派生自 ctx 的组 context。
+// 组 context 会在首个任务失败（fail-fast 模式下）或 Wait 返回时被取消。
+func (p *WorkerPool) NewGroup(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &Group{
+		pool:     p,
+		ctx:      ctx,
+		cancel:   cancel,
+		failFast: true,
+	}
+	return g, ctx
+}
+
+// SetLimit 设置组内任务的最大并发数，n <= 0 表示不限制（默认）。
+// 须在调用 Go 之前设置。
+func (g *Group) SetLimit(n int) *Group {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limit = n
+	return g
+}
+
+// SetFailFast 设置任务失败时是否取消整组，默认为 true。
+// 关闭后所有任务都会执行，可通过 Errors 获取全部错误。
+func (g *Group) SetFailFast(failFast bool) *Group {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.failFast = failFast
+	return g
+}
+
+// Go 将任务提交到组内执行，不会阻塞调用方。
+//
+// fn 接收组 context，应在其取消时尽快返回。组已被取消时 fn 将被直接丢弃。
+// 若所属协程池已停止，或任务在执行前被 Stop 丢弃、被 Shutdown 超时交还，任务以 ErrStopped 失败；
+// 交还的 PendingTask 不会再执行 fn。任务 panic 且协程池设置了 WithPanicHandler 时，任务以 *PanicError 失败。
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	if fn == nil {
+		return
+	}
+	g.wg.Add(1)
+
+	g.mu.Lock()
+	if g.ctx.Err() != nil {
+		g.mu.Unlock()
+		g.wg.Done()
+		return
+	}
+	if g.limit > 0 && g.active >= g.limit {
+		g.pending.PushBack(fn)
+		g.mu.Unlock()
+		return
+	}
+	g.active++
+	g.mu.Unlock()
+
+	g.start(fn)
+}
+
+// Wait 阻塞直到组内所有任务完成，返回第一个非 nil 错误。
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(context.Canceled)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return g.errs[0]
+}
+
+// Errors 返回组内任务产生的全部错误，按发生顺序排列。
+// 应在 Wait 返回后调用以获取完整结果。
+func (g *Group) Errors() []error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]error(nil), g.errs...)
+}
+
+// start 将任务提交到协程池，任务结束后调度组内下一个排队任务。
+// 无论任务正常返回、panic 还是未执行即被协程池丢弃，都会调用 done 释放等待计数。
+func (g *Group) start(fn func(ctx context.Context) error) {
+	t := newTaskWithDiscard(func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				g.done(&PanicError{Value: r, Stack: debug.Stack()})
+				panic(r)
+			}
+			g.done(err)
+		}()
+		// 组已取消时，已进入协程池等待队列的任务同样不再执行
+		if g.ctx.Err() != nil {
+			return nil
+		}
+		return fn(g.ctx)
+	}, func() { g.done(ErrStopped) })
+	if !g.pool.trySubmit(t) {
+		g.done(ErrStopped)
+	}
+}
+
+// done 记录任务结果，并在组未取消时启动下一个排队任务。
+func (g *Group) done(err error) {
+	g.mu.Lock()
+	if err != nil {
+		g.errs = append(g.errs, err)
+		if g.failFast {
+			g.cancel(err)
+		}
+	}
+
+	var next func(ctx context.Context) error
+	if g.ctx.Err() != nil {
+		// 组已取消，丢弃所有排队任务
+		for g.pending.Size() > 0 {
+			g.pending.PopFront()
+			g.wg.Done()
+		}
+	} else if g.pending.Size() > 0 {
+		next = g.pending.PopFront()
+	}
+	if next == nil {
+		g.active--
+	}
+	g.mu.Unlock()
+
+	if next != nil {
+		g.start(next)
+	}
+	g.wg.Done()
+}
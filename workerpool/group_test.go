@@ -0,0 +1,227 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestGroupWait 测试等待组内任务完成
+func TestGroupWait(t *testing.T) {
+	pool := New(4)
+	defer pool.Stop()
+
+	g, _ := pool.NewGroup(context.Background())
+	var counter int32
+	for i := 0; i < 10; i++ {
+		g.Go(func(ctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&counter, 1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait should return nil, got %v", err)
+	}
+	if counter != 10 {
+		t.Errorf("All group tasks should complete, expected 10, got %d", counter)
+	}
+}
+
+// TestGroupFailFast 测试任务失败后取消整组
+func TestGroupFailFast(t *testing.T) {
+	pool := New(4)
+	defer pool.Stop()
+
+	g, ctx := pool.NewGroup(context.Background())
+	g.SetLimit(1)
+	errBoom := errors.New("boom")
+	var ran int32
+	g.Go(func(ctx context.Context) error { return errBoom })
+	for i := 0; i < 5; i++ {
+		g.Go(func(ctx context.Context) error {
+			atomic.AddInt32(&ran, 1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); !errors.Is(err, errBoom) {
+		t.Errorf("Wait should return first error, got %v", err)
+	}
+	if ran != 0 {
+		t.Errorf("Queued tasks should be skipped after failure, got %d executed", ran)
+	}
+	if !errors.Is(context.Cause(ctx), errBoom) {
+		t.Errorf("Group context should be canceled with the error, got %v", context.Cause(ctx))
+	}
+}
+
+// TestGroupCollectErrors 测试关闭 fail-fast 后收集全部错误
+func TestGroupCollectErrors(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+
+	g, _ := pool.NewGroup(context.Background())
+	g.SetFailFast(false)
+	for i := 0; i < 3; i++ {
+		g.Go(func(ctx context.Context) error { return errors.New("failed") })
+	}
+	g.Go(func(ctx context.Context) error { return nil })
+
+	if err := g.Wait(); err == nil {
+		t.Error("Wait should return an error")
+	}
+	if errs := g.Errors(); len(errs) != 3 {
+		t.Errorf("Expected 3 collected errors, got %d", len(errs))
+	}
+}
+
+// TestGroupLimit 测试组内并发限制不阻塞其他任务
+func TestGroupLimit(t *testing.T) {
+	pool := New(4)
+	defer pool.Stop()
+
+	g, _ := pool.NewGroup(context.Background())
+	g.SetLimit(2)
+	var running, peak int32
+	release := make(chan struct{})
+	for i := 0; i < 6; i++ {
+		g.Go(func(ctx context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	// 组内任务受限时，协程池的其他任务仍可执行
+	done := make(chan struct{})
+	pool.Submit(func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Unrelated task should not be blocked by group limit")
+	}
+	if size := pool.WaitingQueueSize(); size != 0 {
+		t.Errorf("Group queued tasks should not occupy pool queue, got %d", size)
+	}
+
+	close(release)
+	g.Wait()
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent group tasks, got %d", peak)
+	}
+}
+
+// TestGroupStoppedPool 测试协程池停止后组任务失败
+func TestGroupStoppedPool(t *testing.T) {
+	pool := New(1)
+	pool.Stop()
+
+	g, _ := pool.NewGroup(context.Background())
+	g.Go(func(ctx context.Context) error { return nil })
+	if err := g.Wait(); !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+}
+
+// TestGroupPanic 测试任务 panic 被恢复时组仍能结束等待
+func TestGroupPanic(t *testing.T) {
+	pool := New(2, WithPanicHandler(func(v any) {}))
+	defer pool.Stop()
+
+	g, _ := pool.NewGroup(context.Background())
+	g.Go(func(ctx context.Context) error { panic("boom") })
+	g.Go(func(ctx context.Context) error { return nil })
+
+	done := make(chan error, 1)
+	go func() { done <- g.Wait() }()
+	select {
+	case err := <-done:
+		var pe *PanicError
+		if !errors.As(err, &pe) || pe.Value != "boom" {
+			t.Errorf("Wait should return *PanicError, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait should return after a task panics")
+	}
+}
+
+// TestGroupDiscardedByStop 测试已进入协程池等待队列的组任务被 Stop 丢弃时以 ErrStopped 结束
+func TestGroupDiscardedByStop(t *testing.T) {
+	pool := New(1)
+	g, _ := pool.NewGroup(context.Background())
+	release := make(chan struct{})
+	var ran int32
+	g.Go(func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	g.Go(func(ctx context.Context) error {
+		atomic.AddInt32(&ran, 1)
+		return nil
+	})
+	waitFor(t, func() bool { return pool.WaitingQueueSize() == 1 })
+
+	stopped := make(chan struct{})
+	go func() {
+		pool.Stop()
+		close(stopped)
+	}()
+	waitFor(t, func() bool { return pool.Stats().Discarded == 1 })
+	close(release)
+	<-stopped
+
+	done := make(chan error, 1)
+	go func() { done <- g.Wait() }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrStopped) {
+			t.Errorf("Expected ErrStopped, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait should return after queued tasks are discarded")
+	}
+	if atomic.LoadInt32(&ran) != 0 {
+		t.Error("Discarded task should not run")
+	}
+}
+
+// TestGroupFailFastQueued 测试组取消后已进入协程池等待队列的任务不再执行
+func TestGroupFailFastQueued(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	g, _ := pool.NewGroup(context.Background())
+	errBoom := errors.New("boom")
+	release := make(chan struct{})
+	var ran int32
+	g.Go(func(ctx context.Context) error {
+		<-release
+		return errBoom
+	})
+	g.Go(func(ctx context.Context) error {
+		atomic.AddInt32(&ran, 1)
+		return nil
+	})
+	waitFor(t, func() bool { return pool.WaitingQueueSize() == 1 })
+	close(release)
+
+	if err := g.Wait(); !errors.Is(err, errBoom) {
+		t.Errorf("Wait should return first error, got %v", err)
+	}
+	if errs := g.Errors(); len(errs) != 1 {
+		t.Errorf("Skipped task should not add errors, got %v", errs)
+	}
+	if atomic.LoadInt32(&ran) != 0 {
+		t.Error("Task queued in the pool should be skipped after failure")
+	}
+}
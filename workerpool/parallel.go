@@ -0,0 +1,165 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wsshow/op/generator"
+	"github.com/wsshow/op/linq"
+	"github.com/wsshow/op/slice"
+)
+
+// Source 是并行辅助函数的输入序列。
+// 实现依次将元素传给 yield，yield 返回 false 时应立即停止并返回 nil；
+// 读取输入失败时返回相应错误。
+type Source[T any] func(yield func(item T) bool) error
+
+// FromValues 将切片作为输入序列。
+func FromValues[T any](items []T) Source[T] {
+	return func(yield func(item T) bool) error {
+		for _, item := range items {
+			if !yield(item) {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+// FromSlice 将 slice.Slice 作为输入序列。
+func FromSlice[T any](s *slice.Slice[T]) Source[T] {
+	return FromValues(s.Data())
+}
+
+// FromLinq 将 linq.Linq 的查询结果作为输入序列。
+// 若链式操作中发生过错误，并行辅助函数将返回该错误。
+func FromLinq[T any](l linq.Linq[T]) Source[T] {
+	return func(yield func(item T) bool) error {
+		if err := l.Error(); err != nil {
+			return err
+		}
+		return FromValues(l.Results())(yield)
+	}
+}
+
+// FromGenerator 将 generator.Generator 作为输入序列，元素按需逐个读取。
+// 提前结束时生成器不会被继续读取。
+func FromGenerator[T any](g *generator.Generator[T]) Source[T] {
+	return func(yield func(item T) bool) error {
+		for {
+			item, done := g.Next()
+			if done || !yield(item) {
+				return nil
+			}
+		}
+	}
+}
+
+// ParallelMap 在协程池中对 src 的每个元素并行执行 fn，并按输入顺序返回结果。
+//
+// 同一时刻最多有 pool.Size() 个元素在执行，输入序列按需读取。任一元素返回错误、
+// ctx 被取消或协程池已停止时，尚未开始的元素不再执行，fn 收到的 ctx 被取消，
+// 函数返回 nil 结果与第一个错误。
+// 协程池设置了 WithPanicHandler 时，fn 中的 panic 被恢复并作为 *PanicError 返回。
+func ParallelMap[T, R any](ctx context.Context, pool *WorkerPool, src Source[T], fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	var mu sync.Mutex
+	var results []R
+	err := parallel(ctx, pool, src, func(T) {
+		var zero R
+		results = append(results, zero)
+	}, func(ctx context.Context, i int, item T) error {
+		r, err := fn(ctx, item)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		results[i] = r
+		mu.Unlock()
+		return nil
+	}, &mu)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ParallelForEach 在协程池中对 src 的每个元素并行执行 fn。
+// 并发度与取消语义同 ParallelMap，返回第一个错误。
+func ParallelForEach[T any](ctx context.Context, pool *WorkerPool, src Source[T], fn func(ctx context.Context, item T) error) error {
+	var mu sync.Mutex
+	return parallel(ctx, pool, src, func(T) {}, func(ctx context.Context, _ int, item T) error {
+		return fn(ctx, item)
+	}, &mu)
+}
+
+// ParallelFilter 在协程池中并行判断 src 的每个元素，按输入顺序返回满足 predicate 的元素。
+// 并发度与取消语义同 ParallelMap。
+func ParallelFilter[T any](ctx context.Context, pool *WorkerPool, src Source[T], predicate func(ctx context.Context, item T) (bool, error)) ([]T, error) {
+	var mu sync.Mutex
+	var items []T
+	var keep []bool
+	err := parallel(ctx, pool, src, func(item T) {
+		items = append(items, item)
+		keep = append(keep, false)
+	}, func(ctx context.Context, i int, item T) error {
+		ok, err := predicate(ctx, item)
+		if err != nil || !ok {
+			return err
+		}
+		mu.Lock()
+		keep[i] = true
+		mu.Unlock()
+		return nil
+	}, &mu)
+	if err != nil {
+		return nil, err
+	}
+	var kept []T
+	for i, item := range items {
+		if keep[i] {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
+
+// parallel 是并行辅助函数的公共实现。
+//
+// 每读取一个元素，先在持有 mu 的情况下调用 grow 为其预留结果位置，
+// 再通过任务组提交 fn，fn 写入结果时同样需持有 mu。
+// 信号量限制同时执行的元素数，从而按需读取输入序列。
+func parallel[T any](ctx context.Context, pool *WorkerPool, src Source[T], grow func(item T), fn func(ctx context.Context, i int, item T) error, mu *sync.Mutex) error {
+	g, gctx := pool.NewGroup(ctx)
+	sem := make(chan struct{}, pool.Size())
+
+	n := 0
+	srcErr := src(func(item T) bool {
+		select {
+		case sem <- struct{}{}:
+		case <-gctx.Done():
+			return false
+		}
+		i := n
+		n++
+		mu.Lock()
+		grow(item)
+		mu.Unlock()
+		g.Go(func(ctx context.Context) error {
+			// fn panic 时同样释放信号量；任务未执行即被丢弃时组被取消，读取循环随之退出
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fn(ctx, i, item)
+		})
+		return true
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	if srcErr != nil {
+		return srcErr
+	}
+	return ctx.Err()
+}
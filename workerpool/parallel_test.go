@@ -0,0 +1,183 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wsshow/op/generator"
+	"github.com/wsshow/op/linq"
+	"github.com/wsshow/op/slice"
+)
+
+// TestParallelMap 测试并行映射保持输入顺序
+func TestParallelMap(t *testing.T) {
+	pool := New(4)
+	defer pool.Stop()
+
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+	results, err := ParallelMap(context.Background(), pool, FromValues(items), func(_ context.Context, v int) (int, error) {
+		time.Sleep(time.Duration(100-v) * time.Microsecond)
+		return v * v, nil
+	})
+	if err != nil {
+		t.Fatalf("ParallelMap failed: %v", err)
+	}
+	if len(results) != 100 {
+		t.Fatalf("Expected 100 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if r != i*i {
+			t.Fatalf("Result %d out of order: got %d", i, r)
+		}
+	}
+}
+
+// TestParallelMapError 测试出错后提前取消剩余元素
+func TestParallelMapError(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+
+	errBad := errors.New("bad item")
+	var calls int32
+	results, err := ParallelMap(context.Background(), pool, FromValues(make([]int, 1000)), func(ctx context.Context, _ int) (int, error) {
+		if atomic.AddInt32(&calls, 1) == 5 {
+			return 0, errBad
+		}
+		return 1, nil
+	})
+	if !errors.Is(err, errBad) || results != nil {
+		t.Errorf("Expected errBad and nil results, got %v, %v", err, results)
+	}
+	if n := atomic.LoadInt32(&calls); n > 10 {
+		t.Errorf("Remaining items should be skipped after error, got %d calls", n)
+	}
+}
+
+// TestParallelMapCancel 测试 ctx 取消后返回 ctx 的错误
+func TestParallelMapCancel(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	_, err := ParallelMap(ctx, pool, FromValues(make([]int, 1000)), func(context.Context, int) (int, error) {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			cancel()
+		}
+		return 0, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestParallelForEach 测试并行遍历 slice.Slice
+func TestParallelForEach(t *testing.T) {
+	pool := New(3)
+	defer pool.Stop()
+
+	var sum int64
+	err := ParallelForEach(context.Background(), pool, FromSlice(slice.New(1, 2, 3, 4, 5)), func(_ context.Context, v int) error {
+		atomic.AddInt64(&sum, int64(v))
+		return nil
+	})
+	if err != nil || sum != 15 {
+		t.Errorf("Expected sum 15, got %d, %v", sum, err)
+	}
+}
+
+// TestParallelFilter 测试并行过滤 linq.Linq 并保持顺序
+func TestParallelFilter(t *testing.T) {
+	pool := New(3)
+	defer pool.Stop()
+
+	src := FromLinq(linq.From([]int{1, 2, 3, 4, 5, 6, 7, 8}).Where(func(v int) bool { return v > 2 }))
+	evens, err := ParallelFilter(context.Background(), pool, src, func(_ context.Context, v int) (bool, error) {
+		return v%2 == 0, nil
+	})
+	if err != nil {
+		t.Fatalf("ParallelFilter failed: %v", err)
+	}
+	if len(evens) != 3 || evens[0] != 4 || evens[1] != 6 || evens[2] != 8 {
+		t.Errorf("Expected [4 6 8], got %v", evens)
+	}
+}
+
+// TestParallelGenerator 测试按需读取生成器输入
+func TestParallelGenerator(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+
+	g := generator.NewGenerator(func(y generator.Yield[string]) {
+		for _, s := range []string{"a", "b", "c"} {
+			y.Yield(s)
+		}
+	})
+	results, err := ParallelMap(context.Background(), pool, FromGenerator(g), func(_ context.Context, s string) (string, error) {
+		return s + s, nil
+	})
+	if err != nil {
+		t.Fatalf("ParallelMap failed: %v", err)
+	}
+	if len(results) != 3 || results[0] != "aa" || results[2] != "cc" {
+		t.Errorf("Expected [aa bb cc], got %v", results)
+	}
+}
+
+// TestParallelStopped 测试协程池已停止时返回 ErrStopped
+func TestParallelStopped(t *testing.T) {
+	pool := New(1)
+	pool.Stop()
+	err := ParallelForEach(context.Background(), pool, FromValues([]int{1, 2}), func(context.Context, int) error {
+		return nil
+	})
+	if !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+}
+
+// TestParallelPanic 测试元素 panic 被恢复时并行辅助函数返回 *PanicError 而不会阻塞
+func TestParallelPanic(t *testing.T) {
+	pool := New(2, WithPanicHandler(func(v any) {}))
+	defer pool.Stop()
+
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := ParallelMap(context.Background(), pool, FromValues(items), func(ctx context.Context, item int) (int, error) {
+			if item%5 == 0 {
+				panic("bad item")
+			}
+			return item, nil
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		var pe *PanicError
+		if !errors.As(err, &pe) {
+			t.Errorf("Expected *PanicError, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ParallelMap should return after an element panics")
+	}
+
+	// 信号量已释放，协程池仍可继续用于并行处理
+	var sum int32
+	err := ParallelForEach(context.Background(), pool, FromValues(items), func(ctx context.Context, item int) error {
+		atomic.AddInt32(&sum, int32(item))
+		return nil
+	})
+	if err != nil || sum != 190 {
+		t.Errorf("Expected sum 190 without error, got %d, %v", sum, err)
+	}
+}
@@ -0,0 +1,151 @@
+package workerpool
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/wsshow/op/deque"
+)
+
+// PauseDispatch 暂停向工作协程分派任务，调用后立即返回。
+//
+// 正在执行的任务不受影响，暂停期间提交的任务进入等待队列，直到 Resume 后再分派。
+// 暂停不占用工作协程，空闲的工作协程仍会按空闲超时回收。
+// 协程池停止时暂停自动失效，StopWait 与 Shutdown 仍会执行已排队的任务。
+func (p *WorkerPool) PauseDispatch() {
+	if !p.pause.manual.Swap(true) {
+		p.wake()
+	}
+}
+
+// Resume 恢复由 PauseDispatch 暂停的任务分派。
+// 不影响通过 Pause 发起的暂停以及通过 PauseKey 暂停的键。
+func (p *WorkerPool) Resume() {
+	if p.pause.manual.Swap(false) {
+		p.wake()
+	}
+}
+
+// IsPaused 返回协程池当前是否暂停分派，包括 PauseDispatch 与 Pause 发起的暂停。
+func (p *WorkerPool) IsPaused() bool {
+	return p.pause.paused()
+}
+
+// PauseKey 暂停分派标记为 key 的任务，其他任务不受影响，调用后立即返回。
+//
+// 任务的标记来自 SubmitTagged 或 SubmitKeyed。被暂停的任务保持提交顺序，
+// 在 ResumeKey 后优先于等待队列中的其他任务分派。
+func (p *WorkerPool) PauseKey(key string) {
+	p.pause.mu.Lock()
+	defer p.pause.mu.Unlock()
+	if p.pause.keys == nil {
+		p.pause.keys = make(map[string]struct{})
+	}
+	p.pause.keys[key] = struct{}{}
+	p.pause.keyCount.Store(int32(len(p.pause.keys)))
+}
+
+// ResumeKey 恢复分派标记为 key 的任务。
+func (p *WorkerPool) ResumeKey(key string) {
+	p.pause.mu.Lock()
+	if _, ok := p.pause.keys[key]; !ok {
+		p.pause.mu.Unlock()
+		return
+	}
+	delete(p.pause.keys, key)
+	p.pause.keyCount.Store(int32(len(p.pause.keys)))
+	p.pause.mu.Unlock()
+
+	p.pause.resumed.Store(true)
+	p.wake()
+}
+
+// IsKeyPaused 返回标记为 key 的任务当前是否暂停分派。
+func (p *WorkerPool) IsKeyPaused(key string) bool {
+	return p.pause.keyPaused(key)
+}
+
+// pauseState 记录协程池的暂停状态，由调用方写入、dispatch 协程读取。
+type pauseState struct {
+	manual  atomic.Bool  // PauseDispatch 发起的暂停
+	holds   atomic.Int32 // 进行中的 Pause 调用数
+	resumed atomic.Bool  // 有键被恢复，dispatch 协程需释放对应的任务
+
+	mu       sync.RWMutex
+	keys     map[string]struct{}
+	keyCount atomic.Int32
+}
+
+// paused 返回是否暂停分派所有任务。
+func (s *pauseState) paused() bool {
+	return s.manual.Load() || s.holds.Load() > 0
+}
+
+// keyPaused 返回 key 是否被暂停。
+func (s *pauseState) keyPaused(key string) bool {
+	if s.keyCount.Load() == 0 {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.keys[key]
+	return ok
+}
+
+// heldTasks 保存因键被暂停而搁置的任务，仅由 dispatch 协程访问。
+type heldTasks struct {
+	queues map[string]*deque.Deque[*task]
+	count  int
+}
+
+// push 将任务搁置到其键的队列尾部。
+func (h *heldTasks) push(t *task) {
+	if h.queues == nil {
+		h.queues = make(map[string]*deque.Deque[*task])
+	}
+	q, ok := h.queues[t.key]
+	if !ok {
+		q = deque.New[*task]()
+		h.queues[t.key] = q
+	}
+	q.PushBack(t)
+	h.count++
+}
+
+// release 取出所有满足 ready 的键下搁置的任务，同一键的任务保持搁置顺序。
+func (h *heldTasks) release(ready func(key string) bool) []*task {
+	var tasks []*task
+	for key, q := range h.queues {
+		if !ready(key) {
+			continue
+		}
+		for q.Size() > 0 {
+			tasks = append(tasks, q.PopFront())
+		}
+		delete(h.queues, key)
+	}
+	h.count -= len(tasks)
+	return tasks
+}
+
+// holdFront 在队首任务的键被暂停时将其搁置并返回 true。
+func (p *WorkerPool) holdFront() bool {
+	front := p.waitingQueue.Front()
+	if !p.pause.keyPaused(front.key) {
+		return false
+	}
+	p.held.push(p.waitingQueue.PopFront())
+	p.updateWaiting()
+	return true
+}
+
+// releaseHeld 将已恢复的键下搁置的任务放回等待队列头部。
+// all 为 true 时释放全部搁置任务，用于协程池停止时。
+func (p *WorkerPool) releaseHeld(all bool) {
+	ready := func(key string) bool { return all || !p.pause.keyPaused(key) }
+	tasks := p.held.release(ready)
+	for i := len(tasks) - 1; i >= 0; i-- {
+		p.waitingQueue.PushFront(tasks[i])
+	}
+	p.updateWaiting()
+}
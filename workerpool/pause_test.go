@@ -0,0 +1,155 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPauseDispatch 测试非阻塞暂停与恢复
+func TestPauseDispatch(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	pool.PauseDispatch()
+	if !pool.IsPaused() {
+		t.Error("Pool should be paused")
+	}
+
+	var counter int32
+	for i := 0; i < 3; i++ {
+		pool.Submit(func() { atomic.AddInt32(&counter, 1) })
+	}
+	// 正在执行的任务不受暂停影响
+	close(release)
+	time.Sleep(20 * time.Millisecond)
+	if n := atomic.LoadInt32(&counter); n != 0 {
+		t.Errorf("No task should run while paused, got %d", n)
+	}
+	if n := pool.WaitingQueueSize(); n != 3 {
+		t.Errorf("Expected 3 queued tasks, got %d", n)
+	}
+	if s := pool.Stats(); s.BusyWorkers != 0 {
+		t.Errorf("Pause should not occupy workers, got %d busy", s.BusyWorkers)
+	}
+
+	pool.Resume()
+	if pool.IsPaused() {
+		t.Error("Pool should not be paused after Resume")
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&counter) == 3 })
+}
+
+// TestPauseKey 测试按键暂停，其他任务正常执行
+func TestPauseKey(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+
+	pool.PauseKey("slow")
+	if !pool.IsKeyPaused("slow") || pool.IsKeyPaused("fast") {
+		t.Error("Only key slow should be paused")
+	}
+
+	var mu sync.Mutex
+	var order []string
+	record := func(s string) func() {
+		return func() {
+			mu.Lock()
+			order = append(order, s)
+			mu.Unlock()
+		}
+	}
+	pool.SubmitKeyed("slow", record("s1"))
+	pool.SubmitKeyed("slow", record("s2"))
+	pool.SubmitTagged("fast", record("f1"))
+	pool.Submit(record("u1"))
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(order) == 2
+	})
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	if len(order) != 2 {
+		t.Errorf("Paused key should not run, got %v", order)
+	}
+	mu.Unlock()
+	if n := pool.WaitingQueueSize(); n != 2 {
+		t.Errorf("Expected 2 held tasks, got %d", n)
+	}
+
+	pool.ResumeKey("slow")
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(order) == 4
+	})
+	mu.Lock()
+	if order[2] != "s1" || order[3] != "s2" {
+		t.Errorf("Held tasks should run in submission order, got %v", order)
+	}
+	mu.Unlock()
+}
+
+// TestPauseStopWait 测试暂停期间 StopWait 仍执行所有排队任务
+func TestPauseStopWait(t *testing.T) {
+	pool := New(1)
+	pool.PauseDispatch()
+	pool.PauseKey("k")
+
+	var counter int32
+	pool.Submit(func() { atomic.AddInt32(&counter, 1) })
+	pool.SubmitTagged("k", func() { atomic.AddInt32(&counter, 1) })
+	pool.StopWait()
+	if counter != 2 {
+		t.Errorf("StopWait should run paused tasks, got %d", counter)
+	}
+}
+
+// TestPauseResumeIndependent 测试 Resume 不会解除 Pause 发起的暂停
+func TestPauseResumeIndependent(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pauseDone := make(chan struct{})
+	go func() {
+		pool.Pause(ctx)
+		close(pauseDone)
+	}()
+	waitFor(t, pool.IsPaused)
+
+	pool.PauseDispatch()
+	pool.Resume()
+	if !pool.IsPaused() {
+		t.Error("Resume should not lift a pause started by Pause")
+	}
+	cancel()
+	<-pauseDone
+	if pool.IsPaused() {
+		t.Error("Pool should not be paused after Pause returns")
+	}
+}
+
+// waitFor 轮询等待条件成立，超时则测试失败。
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
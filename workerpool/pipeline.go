@@ -0,0 +1,303 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// StageOption 定义流水线阶段的可选配置函数。
+type StageOption func(*stageConfig)
+
+// stageConfig 是流水线阶段的配置。
+type stageConfig struct {
+	buffer    int
+	unordered bool
+}
+
+// WithStageBuffer 设置阶段输出缓冲区的大小，默认与阶段并发数相同。
+// 下游处理不及时导致缓冲区写满时，本阶段停止接收新元素，从而向上游施加背压。
+func WithStageBuffer(n int) StageOption {
+	return func(c *stageConfig) {
+		c.buffer = max(n, 0)
+	}
+}
+
+// WithUnordered 使阶段按完成顺序输出结果，而不是默认的输入顺序。
+// 无序输出避免了慢元素阻塞后续结果，适合下游不关心顺序的场景。
+func WithUnordered() StageOption {
+	return func(c *stageConfig) {
+		c.unordered = true
+	}
+}
+
+// Pipeline 是由多个阶段组成的数据流水线。
+//
+// 每个阶段拥有独立的协程池与并发数，阶段之间通过有界缓冲区连接。
+// 任一阶段或数据源返回错误时，流水线的 context 被取消，所有阶段停止接收新元素，
+// 流水线以该错误结束。流水线通过 NewPipeline 创建，通过 AddStage 追加阶段，
+// 并且必须通过 Collect 或 ForEach 消费输出，否则各阶段的协程不会退出。
+type Pipeline struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	err    error
+	stages []*stage
+}
+
+// Stream 是流水线中某个阶段（或数据源）的输出，元素类型为 T。
+type Stream[T any] struct {
+	p  *Pipeline
+	ch <-chan T
+}
+
+// StageStats 是流水线阶段的运行统计。
+type StageStats struct {
+	Name        string // 阶段名称
+	Concurrency int    // 阶段的最大并发数
+	Received    uint64 // 已开始处理的元素数
+	Emitted     uint64 // 已输出到下游的元素数
+	Buffered    int    // 输出缓冲区中等待下游读取的元素数
+	Pool        Stats  // 阶段协程池的统计，包括执行耗时分布与失败数
+}
+
+// stage 记录流水线阶段的运行状态。
+type stage struct {
+	name        string
+	concurrency int
+	pool        *WorkerPool
+	received    atomic.Uint64
+	emitted     atomic.Uint64
+	buffered    func() int
+}
+
+// NewPipeline 以 src 为数据源创建流水线，返回数据源的输出流。
+// 数据源在流水线的 context 取消后停止读取，返回的错误将终止流水线。
+func NewPipeline[T any](ctx context.Context, src Source[T]) *Stream[T] {
+	p := &Pipeline{parent: ctx}
+	p.ctx, p.cancel = context.WithCancelCause(ctx)
+
+	out := make(chan T)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+		err := src(func(item T) bool {
+			select {
+			case out <- item:
+				return true
+			case <-p.ctx.Done():
+				return false
+			}
+		})
+		if err != nil {
+			p.fail(err)
+		}
+	}()
+	return &Stream[T]{p: p, ch: out}
+}
+
+// AddStage 在 s 之后追加一个阶段，以 concurrency 个并发在独立的协程池中对每个元素执行 fn。
+//
+// 默认按输入顺序输出结果，可通过 WithUnordered 改为按完成顺序输出。
+// 同一时刻已接收但尚未输出的元素不超过 concurrency 个。fn 返回错误时流水线被取消，
+// 错误中包含阶段名称，可通过 errors.Is 判断原始错误。
+func AddStage[In, Out any](s *Stream[In], name string, concurrency int, fn func(ctx context.Context, item In) (Out, error), opts ...StageOption) *Stream[Out] {
+	concurrency = max(concurrency, 1)
+	cfg := stageConfig{buffer: concurrency}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	p := s.p
+	out := make(chan Out, cfg.buffer)
+	st := &stage{
+		name:        name,
+		concurrency: concurrency,
+		pool:        New(concurrency),
+		buffered:    func() int { return len(out) },
+	}
+	p.mu.Lock()
+	p.stages = append(p.stages, st)
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+		runStage(p, st, s.ch, out, fn, !cfg.unordered)
+	}()
+	return &Stream[Out]{p: p, ch: out}
+}
+
+// Collect 消费流中的全部元素并按输出顺序返回，等待流水线结束后返回第一个错误。
+func (s *Stream[T]) Collect() ([]T, error) {
+	var items []T
+	err := s.ForEach(func(item T) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ForEach 在调用方协程中依次对流中的元素执行 fn，等待流水线结束后返回第一个错误。
+// fn 返回错误时流水线被取消，并以该错误结束。
+func (s *Stream[T]) ForEach(fn func(item T) error) error {
+	for item := range s.ch {
+		if err := fn(item); err != nil {
+			s.p.fail(err)
+			break
+		}
+	}
+	return s.p.wait()
+}
+
+// Pipeline 返回流所属的流水线。
+func (s *Stream[T]) Pipeline() *Pipeline {
+	return s.p
+}
+
+// Stats 按添加顺序返回各阶段的运行统计。
+func (p *Pipeline) Stats() []StageStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]StageStats, len(p.stages))
+	for i, st := range p.stages {
+		stats[i] = StageStats{
+			Name:        st.name,
+			Concurrency: st.concurrency,
+			Received:    st.received.Load(),
+			Emitted:     st.emitted.Load(),
+			Buffered:    st.buffered(),
+			Pool:        st.pool.Stats(),
+		}
+	}
+	return stats
+}
+
+// fail 记录第一个错误并取消流水线。
+func (p *Pipeline) fail(err error) {
+	p.mu.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+	p.cancel(err)
+}
+
+// wait 等待所有阶段退出，返回第一个错误；未出错但父 context 已取消时返回其错误。
+func (p *Pipeline) wait() error {
+	p.wg.Wait()
+	p.cancel(nil)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	return p.parent.Err()
+}
+
+// runStage 是阶段的主循环：从 in 接收元素并提交到阶段协程池执行，将结果写入 out。
+//
+// 信号量在结果写入 out 之后才释放，因此下游阻塞时阶段停止接收新元素。
+// 有序模式下每个元素占用一个结果槽，槽按接收顺序排队，由单独的协程依次输出。
+func runStage[In, Out any](p *Pipeline, st *stage, in <-chan In, out chan<- Out, fn func(ctx context.Context, item In) (Out, error), ordered bool) {
+	ctx := p.ctx
+	sem := make(chan struct{}, st.concurrency)
+	var tasks sync.WaitGroup
+
+	emit := func(r Out) {
+		select {
+		case out <- r:
+			st.emitted.Add(1)
+		case <-ctx.Done():
+		}
+	}
+
+	var slots chan chan Out
+	emitted := make(chan struct{})
+	if ordered {
+		slots = make(chan chan Out, st.concurrency)
+		go func() {
+			defer close(emitted)
+			for slot := range slots {
+				if r, ok := <-slot; ok {
+					emit(r)
+				}
+				<-sem
+			}
+		}()
+	} else {
+		close(emitted)
+	}
+
+receive:
+	for {
+		var item In
+		select {
+		case v, ok := <-in:
+			if !ok {
+				break receive
+			}
+			item = v
+		case <-ctx.Done():
+			break receive
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break receive
+		}
+		st.received.Add(1)
+
+		var slot chan Out
+		if ordered {
+			// slots 的容量与信号量相同，此处不会阻塞
+			slot = make(chan Out, 1)
+			slots <- slot
+		}
+		deliver := func(r Out, ok bool) {
+			if ordered {
+				if ok {
+					slot <- r
+				}
+				close(slot)
+				return
+			}
+			if ok {
+				emit(r)
+			}
+			<-sem
+		}
+
+		tasks.Add(1)
+		st.pool.SubmitErr(func() error {
+			defer tasks.Done()
+			if ctx.Err() != nil {
+				var zero Out
+				deliver(zero, false)
+				return nil
+			}
+			r, err := fn(ctx, item)
+			if err != nil {
+				p.fail(fmt.Errorf("workerpool: stage %q: %w", st.name, err))
+			}
+			deliver(r, err == nil)
+			return err
+		})
+	}
+
+	tasks.Wait()
+	if ordered {
+		close(slots)
+	}
+	<-emitted
+	st.pool.Stop()
+}
@@ -0,0 +1,177 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPipelineOrdered 测试多阶段流水线的类型转换与有序输出
+func TestPipelineOrdered(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	src := NewPipeline(context.Background(), FromValues(items))
+	parsed := AddStage(src, "format", 4, func(_ context.Context, v int) (string, error) {
+		time.Sleep(time.Duration(rand.IntN(500)) * time.Microsecond)
+		return strconv.Itoa(v), nil
+	})
+	doubled := AddStage(parsed, "double", 3, func(_ context.Context, s string) (string, error) {
+		time.Sleep(time.Duration(rand.IntN(500)) * time.Microsecond)
+		return s + s, nil
+	})
+
+	results, err := doubled.Collect()
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(results) != 50 {
+		t.Fatalf("Expected 50 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if want := strconv.Itoa(i) + strconv.Itoa(i); r != want {
+			t.Fatalf("Result %d out of order: got %q, want %q", i, r, want)
+		}
+	}
+
+	stats := doubled.Pipeline().Stats()
+	if len(stats) != 2 || stats[0].Name != "format" || stats[1].Name != "double" {
+		t.Fatalf("Unexpected stage stats: %+v", stats)
+	}
+	for _, s := range stats {
+		if s.Received != 50 || s.Emitted != 50 || s.Pool.Completed != 50 {
+			t.Errorf("Stage %s: expected 50 received, emitted and completed, got %+v", s.Name, s)
+		}
+	}
+}
+
+// TestPipelineUnordered 测试无序输出包含全部结果
+func TestPipelineUnordered(t *testing.T) {
+	src := NewPipeline(context.Background(), FromValues([]int{5, 1, 3, 0, 2, 4}))
+	out := AddStage(src, "sleep", 6, func(_ context.Context, v int) (int, error) {
+		time.Sleep(time.Duration(v) * 5 * time.Millisecond)
+		return v, nil
+	}, WithUnordered())
+
+	results, err := out.Collect()
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if results[0] != 0 {
+		t.Errorf("Fastest item should be emitted first, got %v", results)
+	}
+	slices.Sort(results)
+	if !slices.Equal(results, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Expected all items, got %v", results)
+	}
+}
+
+// TestPipelineError 测试阶段出错时取消上游并返回错误
+func TestPipelineError(t *testing.T) {
+	errBad := errors.New("bad item")
+	var read int32
+	src := NewPipeline(context.Background(), func(yield func(int) bool) error {
+		for i := 0; ; i++ {
+			atomic.AddInt32(&read, 1)
+			if !yield(i) {
+				return nil
+			}
+		}
+	})
+	first := AddStage(src, "first", 2, func(_ context.Context, v int) (int, error) {
+		return v, nil
+	})
+	second := AddStage(first, "second", 2, func(ctx context.Context, v int) (int, error) {
+		if v == 10 {
+			return 0, errBad
+		}
+		return v, nil
+	})
+
+	_, err := second.Collect()
+	if !errors.Is(err, errBad) {
+		t.Fatalf("Expected errBad, got %v", err)
+	}
+	if n := atomic.LoadInt32(&read); n > 30 {
+		t.Errorf("Source should stop shortly after the error, read %d items", n)
+	}
+}
+
+// TestPipelineBackpressure 测试下游缓慢时上游受缓冲区限制
+func TestPipelineBackpressure(t *testing.T) {
+	var read int32
+	src := NewPipeline(context.Background(), func(yield func(int) bool) error {
+		for i := 0; i < 100; i++ {
+			atomic.AddInt32(&read, 1)
+			if !yield(i) {
+				return nil
+			}
+		}
+		return nil
+	})
+	out := AddStage(src, "id", 2, func(_ context.Context, v int) (int, error) {
+		return v, nil
+	}, WithStageBuffer(3))
+
+	count := 0
+	err := out.ForEach(func(int) error {
+		if count == 0 {
+			time.Sleep(20 * time.Millisecond)
+			// 缓冲区 3 个、在途 2 个、数据源阻塞在发送上 1 个，外加已被消费的 1 个
+			if n := atomic.LoadInt32(&read); n > 8 {
+				t.Errorf("Source should be throttled by backpressure, read %d items", n)
+			}
+		}
+		count++
+		return nil
+	})
+	if err != nil || count != 100 {
+		t.Errorf("Expected 100 items, got %d, %v", count, err)
+	}
+}
+
+// TestPipelineConsumerError 测试消费方出错时终止流水线
+func TestPipelineConsumerError(t *testing.T) {
+	errStop := errors.New("stop")
+	src := NewPipeline(context.Background(), FromValues(make([]int, 1000)))
+	out := AddStage(src, "id", 2, func(_ context.Context, v int) (int, error) {
+		return v, nil
+	})
+	n := 0
+	err := out.ForEach(func(int) error {
+		n++
+		if n == 3 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || n != 3 {
+		t.Errorf("Expected errStop after 3 items, got %v after %d", err, n)
+	}
+}
+
+// TestPipelineCancel 测试父 context 取消时返回其错误
+func TestPipelineCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	src := NewPipeline(ctx, FromValues(make([]int, 1000)))
+	out := AddStage(src, "id", 2, func(_ context.Context, v int) (int, error) {
+		return v, nil
+	})
+	n := 0
+	err := out.ForEach(func(int) error {
+		if n++; n == 5 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package workerpool
+
+import "time"
+
+// WithRateLimit 为协程池设置全局令牌桶限流。
+//
+// rate 为每秒允许分派的任务数，burst 为令牌桶容量（允许的突发任务数，最小为 1）。
+// 限流作用于任务从等待队列分派给工作协程的时刻，被限流的任务不会占用工作协程。
+// rate <= 0 表示不限流。
+func WithRateLimit(rate float64, burst int) Option {
+	return func(p *WorkerPool) {
+		if rate > 0 {
+			p.limiter.global = newTokenBucket(rate, burst)
+		}
+	}
+}
+
+// WithKeyRateLimit 为带键任务（见 SubmitTagged）设置按键令牌桶限流。
+//
+// 相同键的任务共享一个令牌桶，不同键之间互不影响；未带键的任务不受此限制。
+// 与 WithRateLimit 同时使用时，任务需同时满足两者才会被分派。
+// rate <= 0 表示不限流。
+func WithKeyRateLimit(rate float64, burst int) Option {
+	return func(p *WorkerPool) {
+		if rate > 0 {
+			p.limiter.keyRate = rate
+			p.limiter.keyBurst = max(burst, 1)
+			p.limiter.keys = make(map[string]*tokenBucket)
+		}
+	}
+}
+
+// SubmitTagged 提交一个带键的任务到协程池中执行。
+//
+// key 用于标识任务所属的租户、主机等资源，配合 WithKeyRateLimit 使相同键的任务
+// 共享限流配额。除此之外与 Submit 行为一致。
+func (p *WorkerPool) SubmitTagged(key string, task func()) {
+	if task != nil {
+		t := newTask(wrapTask(task))
+		t.key = key
+		p.submit(t)
+	}
+}
+
+// rateLimiter 汇总全局与按键的令牌桶，仅由 dispatch 协程访问。
+type rateLimiter struct {
+	global   *tokenBucket
+	keys     map[string]*tokenBucket
+	keyRate  float64
+	keyBurst int
+}
+
+// enabled 返回是否配置了任意限流。
+func (l *rateLimiter) enabled() bool {
+	return l.global != nil || l.keys != nil
+}
+
+// reserve 为任务预留令牌，返回任务可被分派前需要等待的时长。
+func (l *rateLimiter) reserve(t *task, now time.Time) time.Duration {
+	var wait time.Duration
+	if l.global != nil {
+		wait = l.global.reserve(now)
+	}
+	if l.keys != nil && t.key != "" {
+		b, ok := l.keys[t.key]
+		if !ok {
+			b = newTokenBucket(l.keyRate, l.keyBurst)
+			l.keys[t.key] = b
+		}
+		wait = max(wait, b.reserve(now))
+	}
+	return wait
+}
+
+// prune 移除已回满的按键令牌桶，避免键数量持续增长导致内存泄漏。
+// 回满的令牌桶与新建的令牌桶等价，移除后不影响限流效果。
+func (l *rateLimiter) prune(now time.Time) {
+	for key, b := range l.keys {
+		if b.full(now) {
+			delete(l.keys, key)
+		}
+	}
+}
+
+// tokenBucket 是支持预留的令牌桶，令牌数可为负，表示已预留的未来配额。
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket 创建一个初始为满的令牌桶。
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	burst = max(burst, 1)
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// advance 按流逝的时间补充令牌，令牌数不超过桶容量。
+func (b *tokenBucket) advance(now time.Time) {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+// reserve 预留一个令牌，返回令牌可用前需要等待的时长。
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.advance(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// full 返回令牌桶在 now 时刻是否已回满。
+func (b *tokenBucket) full(now time.Time) bool {
+	b.advance(now)
+	return b.tokens >= b.burst
+}
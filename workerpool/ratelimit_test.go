@@ -0,0 +1,102 @@
+package workerpool
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRateLimit 测试全局限流
+func TestRateLimit(t *testing.T) {
+	pool := New(4, WithRateLimit(50, 1))
+	var counter int32
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		pool.Submit(func() { atomic.AddInt32(&counter, 1) })
+	}
+	pool.StopWait()
+	elapsed := time.Since(start)
+
+	if counter != 6 {
+		t.Errorf("All rate limited tasks should complete, expected 6, got %d", counter)
+	}
+	// 突发 1 个，其余 5 个按 20ms 间隔分派
+	if elapsed < 90*time.Millisecond {
+		t.Errorf("Expected rate limit to spread tasks over ~100ms, took %v", elapsed)
+	}
+}
+
+// TestKeyRateLimit 测试按键限流互不影响
+func TestKeyRateLimit(t *testing.T) {
+	pool := New(4, WithKeyRateLimit(10, 1))
+	defer pool.Stop()
+
+	var mu sync.Mutex
+	done := make(map[string]time.Time)
+	var wg sync.WaitGroup
+	wg.Add(3)
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			done[name] = time.Now()
+			mu.Unlock()
+			wg.Done()
+		}
+	}
+
+	start := time.Now()
+	pool.SubmitTagged("a", record("a1"))
+	pool.SubmitTagged("a", record("a2"))
+	pool.SubmitTagged("b", record("b1"))
+	wg.Wait()
+
+	if d := done["b1"].Sub(start); d > 50*time.Millisecond {
+		t.Errorf("Key b should not wait for key a's budget, took %v", d)
+	}
+	if d := done["a2"].Sub(start); d < 90*time.Millisecond {
+		t.Errorf("Second task of key a should wait ~100ms, took %v", d)
+	}
+}
+
+// TestRateLimitStop 测试 Stop 丢弃被限流的任务
+func TestRateLimitStop(t *testing.T) {
+	pool := New(1, WithRateLimit(1, 1))
+	var counter int32
+	for i := 0; i < 3; i++ {
+		pool.Submit(func() { atomic.AddInt32(&counter, 1) })
+	}
+	time.Sleep(20 * time.Millisecond)
+	if size := pool.WaitingQueueSize(); size != 2 {
+		t.Errorf("Rate limited tasks should be counted as waiting, expected 2, got %d", size)
+	}
+
+	pool.Stop()
+	if counter != 1 {
+		t.Errorf("Only the first task should run before Stop, got %d", counter)
+	}
+	if d := pool.Stats().Discarded; d != 2 {
+		t.Errorf("Expected 2 discarded tasks, got %d", d)
+	}
+}
+
+// TestTokenBucket 测试令牌桶的预留与回满
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(10, 2)
+	if w := b.reserve(now); w != 0 {
+		t.Errorf("First reservation should not wait, got %v", w)
+	}
+	if w := b.reserve(now); w != 0 {
+		t.Errorf("Burst reservation should not wait, got %v", w)
+	}
+	if w := b.reserve(now); w != 100*time.Millisecond {
+		t.Errorf("Third reservation should wait 100ms, got %v", w)
+	}
+	if b.full(now.Add(200 * time.Millisecond)) {
+		t.Error("Bucket should not be full after 200ms")
+	}
+	if !b.full(now.Add(300 * time.Millisecond)) {
+		t.Error("Bucket should be full after 300ms")
+	}
+}
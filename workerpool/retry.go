@@ -0,0 +1,117 @@
+package workerpool
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy 定义失败任务的重试策略。
+//
+// 任务失败后不会在工作协程上休眠等待，而是在退避时间到期后重新进入协程池的等待队列，
+// 期间工作协程可继续执行其他任务。零值字段使用 DefaultRetryPolicy 中的对应默认值。
+type RetryPolicy struct {
+	MaxAttempts    int                  // 最大尝试次数（含首次执行）
+	InitialBackoff time.Duration        // 首次重试前的退避时间
+	MaxBackoff     time.Duration        // 退避时间上限
+	Multiplier     float64              // 每次重试退避时间的增长倍数
+	Jitter         float64              // 退避时间的随机抖动比例，取值 [0, 1]，0 表示不抖动
+	Retryable      func(err error) bool // 判断错误是否可重试，nil 表示所有错误均可重试
+}
+
+// DefaultRetryPolicy 返回默认的重试策略：最多尝试 3 次，退避时间从 100ms 开始
+// 按 2 倍增长，上限 10s，带 20% 的随机抖动。
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// SubmitRetry 提交一个按 policy 重试的任务到协程池中执行。
+//
+// 任务返回可重试的错误且未达到最大尝试次数时，将在退避时间后重新排队执行。
+// 每次尝试都会回调 Hook，最终失败才计入 Stats 的失败数。task 为 nil 时将被忽略。
+func (p *WorkerPool) SubmitRetry(policy RetryPolicy, task func() error) {
+	if task != nil {
+		p.submitRetry(policy, task, nil)
+	}
+}
+
+// SubmitRetryWait 提交一个按 policy 重试的任务并阻塞等待其最终结果。
+// 返回最后一次尝试的错误，成功时返回 nil。task 为 nil 时立即返回 nil。
+func (p *WorkerPool) SubmitRetryWait(policy RetryPolicy, task func() error) error {
+	if task == nil {
+		return nil
+	}
+	done := make(chan error, 1)
+	p.submitRetry(policy, task, func(err error) { done <- err })
+	return <-done
+}
+
+// submitRetry 创建带重试状态的任务并提交。
+func (p *WorkerPool) submitRetry(policy RetryPolicy, task func() error, onDone func(error)) {
+	t := newTask(task)
+	t.retry = &retryState{policy: policy.normalize(), attempt: 1, onDone: onDone}
+	p.retrying.Add(1)
+	p.submit(t)
+}
+
+// normalize 使用默认值填充策略中的零值字段。
+func (rp RetryPolicy) normalize() RetryPolicy {
+	def := DefaultRetryPolicy()
+	if rp.MaxAttempts <= 0 {
+		rp.MaxAttempts = def.MaxAttempts
+	}
+	if rp.InitialBackoff <= 0 {
+		rp.InitialBackoff = def.InitialBackoff
+	}
+	if rp.MaxBackoff <= 0 {
+		rp.MaxBackoff = def.MaxBackoff
+	}
+	if rp.Multiplier < 1 {
+		rp.Multiplier = def.Multiplier
+	}
+	rp.Jitter = min(max(rp.Jitter, 0), 1)
+	return rp
+}
+
+// retryState 记录重试任务的策略与已尝试次数，仅由当前执行该任务的工作协程访问。
+type retryState struct {
+	policy  RetryPolicy
+	attempt int
+	onDone  func(error)
+}
+
+// next 判断失败的任务是否需要再次尝试，需要时递增尝试次数并返回 true。
+func (r *retryState) next(err error) bool {
+	if r.attempt >= r.policy.MaxAttempts {
+		return false
+	}
+	if r.policy.Retryable != nil && !r.policy.Retryable(err) {
+		return false
+	}
+	r.attempt++
+	return true
+}
+
+// backoff 返回下一次尝试前的退避时间，按指数增长并叠加随机抖动。
+func (r *retryState) backoff() time.Duration {
+	rp := r.policy
+	d := float64(rp.InitialBackoff) * math.Pow(rp.Multiplier, float64(r.attempt-2))
+	d = min(d, float64(rp.MaxBackoff))
+	if rp.Jitter > 0 {
+		d += d * rp.Jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(d)
+}
+
+// finish 在任务最终成功或放弃重试时回调结果。
+func (r *retryState) finish(err error) {
+	if r.onDone != nil {
+		r.onDone(err)
+	}
+}
@@ -0,0 +1,164 @@
+package workerpool
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSubmitRetry 测试失败任务按策略重试直到成功
+func TestSubmitRetry(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	var attempts int32
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: 5 * time.Millisecond}
+	err := pool.SubmitRetryWait(policy, func() error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Task should eventually succeed, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	s := pool.Stats()
+	if s.Retried != 2 || s.Completed != 1 || s.Failed != 0 {
+		t.Errorf("Expected 2 retries, 1 completed and 0 failed, got %+v", s)
+	}
+}
+
+// TestSubmitRetryExhausted 测试达到最大尝试次数后放弃
+func TestSubmitRetryExhausted(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	var attempts int32
+	errFail := errors.New("fail")
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	err := pool.SubmitRetryWait(policy, func() error {
+		atomic.AddInt32(&attempts, 1)
+		return errFail
+	})
+	if !errors.Is(err, errFail) {
+		t.Errorf("Expected last error, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if f := pool.Stats().Failed; f != 1 {
+		t.Errorf("Only the final failure should be counted, got %d", f)
+	}
+}
+
+// TestSubmitRetryNonRetryable 测试不可重试的错误立即失败
+func TestSubmitRetryNonRetryable(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	errPermanent := errors.New("permanent")
+	var attempts int32
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   func(err error) bool { return !errors.Is(err, errPermanent) },
+	}
+	err := pool.SubmitRetryWait(policy, func() error {
+		atomic.AddInt32(&attempts, 1)
+		return errPermanent
+	})
+	if !errors.Is(err, errPermanent) || attempts != 1 {
+		t.Errorf("Non-retryable error should fail after 1 attempt, got %v after %d", err, attempts)
+	}
+}
+
+// TestSubmitRetryFreesWorker 测试退避期间不占用工作协程
+func TestSubmitRetryFreesWorker(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	var attempts int32
+	pool.SubmitRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: 200 * time.Millisecond}, func() error {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			return errors.New("retry later")
+		}
+		return nil
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan struct{})
+	pool.Submit(func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Worker should be free while a task is backing off")
+	}
+}
+
+// TestSubmitRetryStopWait 测试 StopWait 等待重试任务结束
+func TestSubmitRetryStopWait(t *testing.T) {
+	pool := New(2)
+	var attempts int32
+	pool.SubmitRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond}, func() error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("fail")
+	})
+	pool.StopWait()
+	if attempts != 3 {
+		t.Errorf("StopWait should wait for all retry attempts, got %d", attempts)
+	}
+}
+
+// TestSubmitRetryStop 测试 Stop 丢弃退避中的重试任务
+func TestSubmitRetryStop(t *testing.T) {
+	pool := New(1)
+	done := make(chan error, 1)
+	attempted := make(chan struct{}, 3)
+	go func() {
+		done <- pool.SubmitRetryWait(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second}, func() error {
+			attempted <- struct{}{}
+			return errors.New("fail")
+		})
+	}()
+	<-attempted
+	time.Sleep(10 * time.Millisecond)
+	pool.Stop()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrStopped) {
+			t.Errorf("Discarded retry task should end with ErrStopped, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SubmitRetryWait should return after Stop")
+	}
+}
+
+// TestRetryBackoff 测试指数退避与上限
+func TestRetryBackoff(t *testing.T) {
+	r := &retryState{policy: RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}.normalize(), attempt: 1}
+
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, want := range expected {
+		r.next(errors.New("fail"))
+		if got := r.backoff(); got != want*time.Millisecond {
+			t.Errorf("Backoff %d expected %v, got %v", i, want*time.Millisecond, got)
+		}
+	}
+
+	r = &retryState{policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond, Jitter: 0.5}.normalize(), attempt: 1}
+	r.next(errors.New("fail"))
+	for i := 0; i < 100; i++ {
+		if d := r.backoff(); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("Jittered backoff should stay within ±50%%, got %v", d)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package workerpool
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Clock 抽象调度器使用的时间来源，可在测试中注入可手动推进的实现。
+type Clock interface {
+	// Now 返回当前时间。
+	Now() time.Time
+	// NewTimer 创建一个在 d 之后触发的计时器。
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer 是 Clock 创建的计时器，语义与 time.Timer 一致。
+type Timer interface {
+	// C 返回计时器触发时接收时间的通道。
+	C() <-chan time.Time
+	// Stop 停止计时器，若计时器已触发或已停止则返回 false。
+	Stop() bool
+	// Reset 将计时器重置为在 d 之后触发。
+	Reset(d time.Duration) bool
+}
+
+// realClock 是基于 time 包的默认 Clock 实现。
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+// realTimer 将 *time.Timer 适配为 Timer 接口。
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+
+func (t realTimer) Stop() bool { return t.t.Stop() }
+
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+// RepeatMode 定义周期任务的重复方式。
+type RepeatMode int
+
+const (
+	// FixedRate 按固定频率执行：下一次计划时间 = 上一次计划时间 + 间隔。
+	// 若任务执行耗时超过间隔，下一次将在任务结束后立即执行，错过的周期不会补偿。
+	FixedRate RepeatMode = iota
+	// FixedDelay 按固定延迟执行：下一次计划时间 = 上一次执行结束时间 + 间隔。
+	FixedDelay
+)
+
+// SchedulerOption 定义 Scheduler 的可选配置函数。
+type SchedulerOption func(*Scheduler)
+
+// WithClock 设置调度器使用的时间来源，默认使用系统时间。
+func WithClock(c Clock) SchedulerOption {
+	return func(s *Scheduler) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// Scheduler 是构建在 WorkerPool 之上的定时任务调度器。
+//
+// 定时任务保存在按触发时间排序的最小堆中，由单个调度协程和一个计时器驱动，
+// 可支撑数万个待触发任务；到期的任务被提交到协程池中执行，不会占用调度协程。
+// 周期任务在上一次执行结束后才会安排下一次执行，因此同一任务不会并发执行。
+type Scheduler struct {
+	pool  *WorkerPool
+	clock Clock
+
+	mu      sync.Mutex
+	entries scheduleHeap
+	seq     uint64
+	stopped bool
+
+	wake     chan struct{}
+	stopChan chan struct{}
+	doneChan chan struct{}
+	stopOnce sync.Once
+}
+
+// ScheduledTask 是定时任务的句柄，可用于取消尚未执行的任务或停止周期任务。
+type ScheduledTask struct {
+	s        *Scheduler
+	task     func()
+	at       time.Time
+	interval time.Duration
+	mode     RepeatMode
+	repeat   bool
+	seq      uint64
+	index    int // 在堆中的位置，-1 表示不在堆中
+	canceled bool
+}
+
+// NewScheduler 创建并启动一个使用 pool 执行任务的调度器。
+// 调度器需通过 Stop 停止，停止调度器不会停止协程池。
+func NewScheduler(pool *WorkerPool, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		pool:     pool,
+		clock:    realClock{},
+		wake:     make(chan struct{}, 1),
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	go s.run()
+	return s
+}
+
+// SubmitAfter 安排任务在 d 之后提交到协程池执行。d <= 0 时立即提交。
+func (s *Scheduler) SubmitAfter(d time.Duration, task func()) *ScheduledTask {
+	return s.SubmitAt(s.clock.Now().Add(d), task)
+}
+
+// SubmitAt 安排任务在 at 时刻提交到协程池执行。at 早于当前时间时立即提交。
+func (s *Scheduler) SubmitAt(at time.Time, task func()) *ScheduledTask {
+	return s.schedule(&ScheduledTask{task: task, at: at})
+}
+
+// SubmitEvery 安排任务按 interval 周期性执行，首次执行在 interval 之后。
+// mode 指定按固定频率（FixedRate）或固定延迟（FixedDelay）重复。
+// interval <= 0 时返回 nil。
+func (s *Scheduler) SubmitEvery(interval time.Duration, mode RepeatMode, task func()) *ScheduledTask {
+	if interval <= 0 {
+		return nil
+	}
+	return s.schedule(&ScheduledTask{
+		task:     task,
+		at:       s.clock.Now().Add(interval),
+		interval: interval,
+		mode:     mode,
+		repeat:   true,
+	})
+}
+
+// Len 返回等待触发的定时任务数量。
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Stop 停止调度器，所有未触发的定时任务将被丢弃。
+// 已提交到协程池的任务不受影响。
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		s.mu.Lock()
+		s.stopped = true
+		for _, st := range s.entries {
+			st.index = -1
+		}
+		s.entries = nil
+		s.mu.Unlock()
+		close(s.stopChan)
+	})
+	<-s.doneChan
+}
+
+// Cancel 取消定时任务。对于一次性任务，若任务尚未提交到协程池则返回 true；
+// 对于周期任务，取消后不再安排后续执行，若此前未被取消则返回 true。
+// 已提交到协程池的执行不会被中断。
+func (t *ScheduledTask) Cancel() bool {
+	if t == nil {
+		return false
+	}
+	s := t.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.canceled {
+		return false
+	}
+	if t.index < 0 && !t.repeat {
+		return false
+	}
+	t.canceled = true
+	if t.index >= 0 {
+		heap.Remove(&s.entries, t.index)
+	}
+	return true
+}
+
+// schedule 将定时任务加入堆中，并在其成为最早的任务时唤醒调度协程。
+func (s *Scheduler) schedule(t *ScheduledTask) *ScheduledTask {
+	t.s = s
+	t.index = -1
+	if t.task == nil {
+		return t
+	}
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return t
+	}
+	s.push(t)
+	first := t.index == 0
+	s.mu.Unlock()
+
+	if first {
+		s.notify()
+	}
+	return t
+}
+
+// push 在持有锁的情况下将任务加入堆中。
+func (s *Scheduler) push(t *ScheduledTask) {
+	s.seq++
+	t.seq = s.seq
+	heap.Push(&s.entries, t)
+}
+
+// run 是调度协程的主循环，弹出到期任务提交到协程池，并将计时器设置为下一个任务的触发时间。
+func (s *Scheduler) run() {
+	defer close(s.doneChan)
+	timer := s.clock.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		now := s.clock.Now()
+		var due []*ScheduledTask
+		for len(s.entries) > 0 && !s.entries[0].at.After(now) {
+			due = append(due, heap.Pop(&s.entries).(*ScheduledTask))
+		}
+		var next time.Time
+		if len(s.entries) > 0 {
+			next = s.entries[0].at
+		}
+		s.mu.Unlock()
+
+		for _, t := range due {
+			s.fire(t)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C():
+			default:
+			}
+		}
+		if !next.IsZero() {
+			timer.Reset(next.Sub(now))
+			// 计算等待时长期间时间可能已经推进，此时直接进入下一轮
+			if !s.clock.Now().Before(next) {
+				continue
+			}
+		}
+
+		select {
+		case <-timer.C():
+		case <-s.wake:
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// fire 将到期任务提交到协程池，周期任务在执行结束后重新加入堆中。
+func (s *Scheduler) fire(t *ScheduledTask) {
+	run := t.task
+	if t.repeat {
+		run = func() {
+			// 任务 panic 并被 WithPanicHandler 恢复时同样继续调度下一次执行
+			defer s.reschedule(t)
+			t.task()
+		}
+	}
+	// 协程池已停止或任务被丢弃时，周期任务不再继续
+	end := func() {
+		s.mu.Lock()
+		t.canceled = true
+		s.mu.Unlock()
+	}
+	if !s.pool.trySubmit(newTaskWithDiscard(wrapTask(run), end)) {
+		end()
+	}
+}
+
+// reschedule 根据重复方式计算周期任务的下一次触发时间并重新加入堆中。
+func (s *Scheduler) reschedule(t *ScheduledTask) {
+	s.mu.Lock()
+	if t.canceled || s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	now := s.clock.Now()
+	if t.mode == FixedDelay {
+		t.at = now.Add(t.interval)
+	} else {
+		t.at = t.at.Add(t.interval)
+		if t.at.Before(now) {
+			t.at = now
+		}
+	}
+	s.push(t)
+	first := t.index == 0
+	s.mu.Unlock()
+
+	if first {
+		s.notify()
+	}
+}
+
+// notify 唤醒调度协程以重新计算下一次触发时间。
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// scheduleHeap 是按触发时间排序的定时任务最小堆，实现 heap.Interface。
+// 相同触发时间的任务按加入顺序出堆。
+type scheduleHeap []*ScheduledTask
+
+func (h scheduleHeap) Len() int { return len(h) }
+
+func (h scheduleHeap) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+func (h scheduleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scheduleHeap) Push(x any) {
+	t := x.(*ScheduledTask)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *scheduleHeap) Pop() any {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
@@ -0,0 +1,280 @@
+package workerpool
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock 是可手动推进的 Clock 实现，用于测试
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// fakeTimer 是 fakeClock 创建的计时器
+type fakeTimer struct {
+	clock    *fakeClock
+	c        chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1), deadline: c.now.Add(d), active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance 推进时间并触发所有到期的计时器
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		if t.active && !t.deadline.After(c.now) {
+			t.active = false
+			select {
+			case t.c <- c.now:
+			default:
+			}
+		}
+	}
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	was := t.active
+	t.active = false
+	return was
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	was := t.active
+	t.active = true
+	t.deadline = t.clock.now.Add(d)
+	return was
+}
+
+// waitSignal 等待信号或超时失败
+func waitSignal(t *testing.T, ch <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal(msg)
+	}
+}
+
+// expectNoSignal 确认在短时间内没有收到信号
+func expectNoSignal(t *testing.T, ch <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatal(msg)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// TestSchedulerSubmitAfter 测试延迟任务
+func TestSchedulerSubmitAfter(t *testing.T) {
+	pool := New(2)
+	defer pool.Stop()
+	clock := newFakeClock()
+	s := NewScheduler(pool, WithClock(clock))
+	defer s.Stop()
+
+	ran := make(chan struct{}, 1)
+	s.SubmitAfter(30*time.Second, func() { ran <- struct{}{} })
+	if s.Len() != 1 {
+		t.Errorf("Expected 1 pending timer, got %d", s.Len())
+	}
+
+	clock.Advance(29 * time.Second)
+	expectNoSignal(t, ran, "Task should not run before its deadline")
+	clock.Advance(time.Second)
+	waitSignal(t, ran, "Task should run at its deadline")
+	if s.Len() != 0 {
+		t.Errorf("Expected no pending timers, got %d", s.Len())
+	}
+}
+
+// TestSchedulerSubmitAtOrder 测试多个定时任务按时间顺序触发
+func TestSchedulerSubmitAtOrder(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+	clock := newFakeClock()
+	s := NewScheduler(pool, WithClock(clock))
+	defer s.Stop()
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for _, sec := range []int{3, 1, 2} {
+		s.SubmitAt(clock.Now().Add(time.Duration(sec)*time.Second), func() {
+			mu.Lock()
+			order = append(order, sec)
+			mu.Unlock()
+			wg.Done()
+		})
+	}
+	for i := 0; i < 3; i++ {
+		time.Sleep(5 * time.Millisecond)
+		clock.Advance(time.Second)
+	}
+	wg.Wait()
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Errorf("Expected tasks in order [1 2 3], got %v", order)
+	}
+}
+
+// TestSchedulerCancel 测试取消定时任务
+func TestSchedulerCancel(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+	clock := newFakeClock()
+	s := NewScheduler(pool, WithClock(clock))
+	defer s.Stop()
+
+	ran := make(chan struct{}, 1)
+	h := s.SubmitAfter(time.Second, func() { ran <- struct{}{} })
+	if !h.Cancel() {
+		t.Error("Cancel should succeed before the task runs")
+	}
+	if h.Cancel() {
+		t.Error("Second Cancel should return false")
+	}
+	clock.Advance(2 * time.Second)
+	expectNoSignal(t, ran, "Canceled task should not run")
+	if s.Len() != 0 {
+		t.Errorf("Canceled task should be removed, got %d pending", s.Len())
+	}
+}
+
+// TestSchedulerEvery 测试周期任务及其取消
+func TestSchedulerEvery(t *testing.T) {
+	for _, mode := range []RepeatMode{FixedRate, FixedDelay} {
+		pool := New(2)
+		clock := newFakeClock()
+		s := NewScheduler(pool, WithClock(clock))
+
+		ran := make(chan struct{}, 10)
+		h := s.SubmitEvery(5*time.Minute, mode, func() { ran <- struct{}{} })
+		for i := 0; i < 3; i++ {
+			clock.Advance(5 * time.Minute)
+			waitSignal(t, ran, "Periodic task should run every interval")
+			// 等待任务重新加入调度
+			for s.Len() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}
+
+		h.Cancel()
+		clock.Advance(5 * time.Minute)
+		expectNoSignal(t, ran, "Canceled periodic task should not run again")
+		s.Stop()
+		pool.Stop()
+	}
+}
+
+// TestSchedulerEveryPanic 测试周期任务 panic 被恢复后仍继续调度
+func TestSchedulerEveryPanic(t *testing.T) {
+	pool := New(1, WithPanicHandler(func(v any) {}))
+	defer pool.Stop()
+	clock := newFakeClock()
+	s := NewScheduler(pool, WithClock(clock))
+	defer s.Stop()
+
+	ran := make(chan struct{}, 10)
+	h := s.SubmitEvery(time.Minute, FixedDelay, func() {
+		ran <- struct{}{}
+		panic("tick failed")
+	})
+	for i := 0; i < 2; i++ {
+		clock.Advance(time.Minute)
+		waitSignal(t, ran, "Periodic task should keep running after a panic")
+		for s.Len() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if !h.Cancel() {
+		t.Error("Cancel should stop a periodic task that is still scheduled")
+	}
+}
+
+// TestSchedulerFixedRateNoOverlap 测试周期任务不会并发执行
+func TestSchedulerFixedRateNoOverlap(t *testing.T) {
+	pool := New(4)
+	defer pool.Stop()
+	s := NewScheduler(pool)
+	defer s.Stop()
+
+	var running, overlap, count int32
+	s.SubmitEvery(2*time.Millisecond, FixedRate, func() {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.StoreInt32(&overlap, 1)
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&count, 1)
+		atomic.AddInt32(&running, -1)
+	})
+	time.Sleep(60 * time.Millisecond)
+	if atomic.LoadInt32(&overlap) != 0 {
+		t.Error("Periodic task runs should not overlap")
+	}
+	if atomic.LoadInt32(&count) < 3 {
+		t.Errorf("Periodic task should keep running, got %d runs", count)
+	}
+}
+
+// TestSchedulerManyTimers 测试大量定时任务
+func TestSchedulerManyTimers(t *testing.T) {
+	pool := New(8)
+	defer pool.Stop()
+	clock := newFakeClock()
+	s := NewScheduler(pool, WithClock(clock))
+	defer s.Stop()
+
+	const n = 20000
+	var count int32
+	var wg sync.WaitGroup
+	wg.Add(n / 2)
+	handles := make([]*ScheduledTask, n)
+	for i := 0; i < n; i++ {
+		handles[i] = s.SubmitAfter(time.Duration(i%100+1)*time.Second, func() {
+			atomic.AddInt32(&count, 1)
+			wg.Done()
+		})
+	}
+	for i := 0; i < n; i += 2 {
+		handles[i].Cancel()
+	}
+	if s.Len() != n/2 {
+		t.Errorf("Expected %d pending timers, got %d", n/2, s.Len())
+	}
+	clock.Advance(100 * time.Second)
+	wg.Wait()
+	if count != n/2 {
+		t.Errorf("Expected %d runs, got %d", n/2, count)
+	}
+}
@@ -0,0 +1,84 @@
+package workerpool
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/wsshow/op/deque"
+)
+
+// SubmitKeyed 提交一个按键串行执行的任务。
+//
+// 相同 key 的任务按提交顺序依次执行，前一个任务结束后才会分派下一个；
+// 不同 key 的任务仍在协程池的工作协程上并行执行。同一键的后续任务在前一个任务
+// 完成后重新进入等待队列尾部，因此繁忙的键不会长期独占工作协程。
+// key 同时作为任务的标记，参与 WithKeyRateLimit 的按键限流。task 为 nil 时将被忽略。
+func (p *WorkerPool) SubmitKeyed(key string, task func()) {
+	if task == nil {
+		return
+	}
+	t := newTask(wrapTask(task))
+	t.key = key
+	t.serial = true
+	if p.serial.enqueue(t) {
+		p.metrics.submitted.Add(1)
+		return
+	}
+	p.submit(t)
+}
+
+// serialKeys 记录每个键下等待执行的串行任务。
+// 键存在于 queues 中即表示该键有任务正在执行或等待分派。
+type serialKeys struct {
+	mu      sync.Mutex
+	queues  map[string]*deque.Deque[*task]
+	pending atomic.Int32
+}
+
+// enqueue 在键已有任务执行时将 t 加入该键的队列并返回 true；
+// 否则登记该键并返回 false，由调用方直接提交 t。
+func (s *serialKeys) enqueue(t *task) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queues == nil {
+		s.queues = make(map[string]*deque.Deque[*task])
+	}
+	if q, ok := s.queues[t.key]; ok {
+		q.PushBack(t)
+		s.pending.Add(1)
+		return true
+	}
+	s.queues[t.key] = deque.New[*task]()
+	return false
+}
+
+// next 在键的一个任务结束后返回该键的下一个任务，无后续任务时注销该键并返回 nil。
+// 返回的任务仍计入 pending，直到 dispatch 协程收到该任务。
+func (s *serialKeys) next(key string) *task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, ok := s.queues[key]
+	if !ok {
+		return nil
+	}
+	if q.Size() == 0 {
+		delete(s.queues, key)
+		return nil
+	}
+	return q.PopFront()
+}
+
+// drain 取出所有键下等待执行的任务并注销全部键，同一键的任务保持提交顺序。
+func (s *serialKeys) drain() []*task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tasks []*task
+	for key, q := range s.queues {
+		for q.Size() > 0 {
+			tasks = append(tasks, q.PopFront())
+		}
+		delete(s.queues, key)
+	}
+	s.pending.Add(-int32(len(tasks)))
+	return tasks
+}
@@ -0,0 +1,108 @@
+package workerpool
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSubmitKeyedOrder 测试相同键的任务按提交顺序串行执行
+func TestSubmitKeyedOrder(t *testing.T) {
+	pool := New(4)
+	var mu sync.Mutex
+	results := make(map[string][]int)
+	var running [3]int32
+	var overlap atomic.Bool
+
+	for i := 0; i < 20; i++ {
+		for k := 0; k < 3; k++ {
+			key := fmt.Sprintf("key-%d", k)
+			pool.SubmitKeyed(key, func() {
+				if atomic.AddInt32(&running[k], 1) > 1 {
+					overlap.Store(true)
+				}
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				results[key] = append(results[key], i)
+				mu.Unlock()
+				atomic.AddInt32(&running[k], -1)
+			})
+		}
+	}
+	pool.StopWait()
+
+	if overlap.Load() {
+		t.Error("Tasks with the same key should never run concurrently")
+	}
+	for key, seq := range results {
+		if len(seq) != 20 {
+			t.Errorf("Key %s expected 20 tasks, got %d", key, len(seq))
+		}
+		for i, v := range seq {
+			if v != i {
+				t.Errorf("Key %s tasks out of order: %v", key, seq)
+				break
+			}
+		}
+	}
+	if size := pool.WaitingQueueSize(); size != 0 {
+		t.Errorf("Waiting queue should be empty after StopWait, got %d", size)
+	}
+}
+
+// TestSubmitKeyedParallel 测试不同键的任务并行执行
+func TestSubmitKeyedParallel(t *testing.T) {
+	pool := New(3)
+	defer pool.Stop()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	barrier := make(chan struct{})
+	var arrived int32
+	for k := 0; k < 3; k++ {
+		pool.SubmitKeyed(fmt.Sprintf("key-%d", k), func() {
+			defer wg.Done()
+			if atomic.AddInt32(&arrived, 1) == 3 {
+				close(barrier)
+			}
+			select {
+			case <-barrier:
+			case <-time.After(time.Second):
+				t.Error("Tasks with different keys should run in parallel")
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// TestSubmitKeyedStop 测试 Stop 丢弃同键排队任务
+func TestSubmitKeyedStop(t *testing.T) {
+	pool := New(2)
+	var counter int32
+	release := make(chan struct{})
+	pool.SubmitKeyed("k", func() {
+		<-release
+		atomic.AddInt32(&counter, 1)
+	})
+	for i := 0; i < 3; i++ {
+		pool.SubmitKeyed("k", func() { atomic.AddInt32(&counter, 1) })
+	}
+	time.Sleep(10 * time.Millisecond)
+	if size := pool.WaitingQueueSize(); size != 3 {
+		t.Errorf("Expected 3 keyed tasks waiting, got %d", size)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	pool.Stop()
+	if counter != 1 {
+		t.Errorf("Only the running keyed task should complete, got %d", counter)
+	}
+	if d := pool.Stats().Discarded; d != 3 {
+		t.Errorf("Expected 3 discarded keyed tasks, got %d", d)
+	}
+}
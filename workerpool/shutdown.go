@@ -0,0 +1,60 @@
+package workerpool
+
+import "context"
+
+// PendingTask 描述 Shutdown 超时时尚未执行的任务，可用于持久化或转交给其他协程池。
+type PendingTask struct {
+	Run    func() error // 任务函数，可直接执行或通过 SubmitErr 提交到其他协程池
+	Key    string       // 任务的标记，见 SubmitTagged 与 SubmitKeyed
+	Record *TaskRecord  // 命名任务的存储记录，非命名任务为 nil
+}
+
+// ShutdownResult 是 Shutdown 的返回结果。
+type ShutdownResult struct {
+	Pending  []PendingTask // 未执行的任务，按等待队列、延迟队列、暂停搁置、按键串行等待的顺序排列
+	InFlight int           // 放弃等待时仍在执行的任务数
+}
+
+// Shutdown 停止工作协程池，并在 ctx 结束前尽可能执行完所有已排队的任务。
+//
+// 若所有任务在 ctx 结束前执行完毕，返回空结果与 nil。否则停止分派，
+// 立即返回尚未执行的任务、仍在执行的任务数以及 ctx.Err()，不再等待正在执行的任务。
+// 未执行的任务计入丢弃数；其中的重试任务以 ErrStopped 通知 SubmitRetryWait 的调用方，
+// 命名任务的记录保留在存储中未被确认，任务组与批处理器的任务以 ErrStopped 结束，
+// 其 Run 不再执行原任务。放弃等待后，正在执行的任务产生的重试与同一键的后续任务将被丢弃。
+// 调用后不得再次提交任务。
+//
+// 若协程池已通过 Stop 停止，Shutdown 等待停止完成后返回空结果。
+func (p *WorkerPool) Shutdown(ctx context.Context) (ShutdownResult, error) {
+	p.beginStop(true)
+	select {
+	case <-p.stoppedChan:
+		return ShutdownResult{}, nil
+	case <-ctx.Done():
+	}
+
+	p.abortOnce.Do(func() { close(p.abortSignal) })
+	select {
+	case <-p.handoffDone:
+		return p.handoff, ctx.Err()
+	case <-p.stoppedChan:
+		// handoffDone 先于 stoppedChan 关闭，此处再次检查以区分排空完成与已中止
+		select {
+		case <-p.handoffDone:
+			return p.handoff, ctx.Err()
+		default:
+			return ShutdownResult{}, nil
+		}
+	}
+}
+
+// handOff 在 Shutdown 超时后取出所有未执行的任务，供 Shutdown 返回。
+func (p *WorkerPool) handOff() {
+	result := ShutdownResult{InFlight: int(p.running.Load())}
+	for _, t := range p.drainQueued() {
+		p.discard(t)
+		result.Pending = append(result.Pending, PendingTask{Run: t.run, Key: t.key, Record: t.record})
+	}
+	p.handoff = result
+	close(p.handoffDone)
+}
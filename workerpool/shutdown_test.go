@@ -0,0 +1,210 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestShutdownDrained 测试在超时前执行完所有排队任务
+func TestShutdownDrained(t *testing.T) {
+	pool := New(2)
+	var count int32
+	for i := 0; i < 10; i++ {
+		pool.Submit(func() {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&count, 1)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := pool.Shutdown(ctx)
+	if err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+	if len(result.Pending) != 0 || result.InFlight != 0 {
+		t.Errorf("Expected empty result, got %+v", result)
+	}
+	if count != 10 {
+		t.Errorf("Expected 10 tasks to run, got %d", count)
+	}
+	if !pool.Stopped() {
+		t.Error("Pool should be stopped")
+	}
+}
+
+// TestShutdownTimeout 测试超时后返回未执行的任务与正在执行的任务数
+func TestShutdownTimeout(t *testing.T) {
+	pool := New(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	var ran int32
+	for i := 0; i < 3; i++ {
+		pool.Submit(func() { atomic.AddInt32(&ran, 1) })
+	}
+	pool.SubmitKeyed("k", func() { atomic.AddInt32(&ran, 1) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	result, err := pool.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if result.InFlight != 1 {
+		t.Errorf("Expected 1 in-flight task, got %d", result.InFlight)
+	}
+	if len(result.Pending) != 4 {
+		t.Fatalf("Expected 4 pending tasks, got %d", len(result.Pending))
+	}
+	if result.Pending[3].Key != "k" {
+		t.Errorf("Expected keyed task last, got key %q", result.Pending[3].Key)
+	}
+	if ran != 0 {
+		t.Errorf("No queued task should run, got %d", ran)
+	}
+
+	// 未执行的任务可以转交给其他协程池
+	other := New(2)
+	for _, pt := range result.Pending {
+		other.SubmitErr(pt.Run)
+	}
+	other.StopWait()
+	if ran != 4 {
+		t.Errorf("Expected 4 handed-off tasks to run, got %d", ran)
+	}
+
+	close(release)
+	pool.Stop()
+	if s := pool.Stats(); s.Discarded != 4 {
+		t.Errorf("Expected 4 discarded tasks, got %d", s.Discarded)
+	}
+}
+
+// TestShutdownRunningTimeout 测试队列已空但任务仍在执行时 Shutdown 按期限返回
+func TestShutdownRunningTimeout(t *testing.T) {
+	pool := New(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	type outcome struct {
+		result ShutdownResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := pool.Shutdown(ctx)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		if !errors.Is(o.err, context.DeadlineExceeded) {
+			t.Errorf("Expected DeadlineExceeded, got %v", o.err)
+		}
+		if o.result.InFlight != 1 {
+			t.Errorf("Expected 1 in-flight task, got %d", o.result.InFlight)
+		}
+		if len(o.result.Pending) != 0 {
+			t.Errorf("Expected no pending tasks, got %d", len(o.result.Pending))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown should return at the deadline while a task is still running")
+	}
+
+	close(release)
+	pool.Stop()
+}
+
+// TestShutdownNamed 测试超时后命名任务保留存储记录
+func TestShutdownNamed(t *testing.T) {
+	store := NewMemoryStore()
+	pool := New(1, WithQueueStore(store))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Register("block", func([]byte) error {
+		close(started)
+		<-release
+		return nil
+	})
+	pool.Register("job", func([]byte) error { return nil })
+
+	pool.SubmitNamed("block", nil)
+	<-started
+	pool.SubmitNamed("job", []byte("payload"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := pool.Shutdown(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Canceled, got %v", err)
+	}
+	if len(result.Pending) != 1 || result.Pending[0].Record == nil ||
+		result.Pending[0].Record.Name != "job" || string(result.Pending[0].Record.Payload) != "payload" {
+		t.Errorf("Expected pending named task with record, got %+v", result.Pending)
+	}
+
+	close(release)
+	pool.Stop()
+	records, _ := store.Pending()
+	if len(records) != 1 || records[0].Name != "job" {
+		t.Errorf("Expected unrun record to remain in store, got %+v", records)
+	}
+}
+
+// TestShutdownRetry 测试超时后未执行的重试任务以 ErrStopped 结束
+func TestShutdownRetry(t *testing.T) {
+	pool := New(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	done := make(chan error, 1)
+	go func() {
+		done <- pool.SubmitRetryWait(DefaultRetryPolicy(), func() error { return nil })
+	}()
+	for pool.WaitingQueueSize() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result, _ := pool.Shutdown(ctx)
+	if len(result.Pending) != 1 {
+		t.Errorf("Expected 1 pending task, got %d", len(result.Pending))
+	}
+	if err := <-done; !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+	close(release)
+	pool.Stop()
+}
+
+// TestShutdownAfterStop 测试协程池已停止时 Shutdown 直接返回
+func TestShutdownAfterStop(t *testing.T) {
+	pool := New(1)
+	pool.Stop()
+	result, err := pool.Shutdown(context.Background())
+	if err != nil || len(result.Pending) != 0 {
+		t.Errorf("Expected empty result, got %+v, %v", result, err)
+	}
+}
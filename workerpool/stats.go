@@ -0,0 +1,193 @@
+package workerpool
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// histogramBounds 是队列等待时间与执行时间直方图的桶上界。
+// 超过最后一个上界的观测值计入溢出桶。
+var histogramBounds = [...]time.Duration{
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// TaskInfo 描述一次任务执行的时间信息，传递给 Hook。
+type TaskInfo struct {
+	Submitted time.Time     // 任务提交时间
+	Started   time.Time     // 任务开始执行时间
+	QueueWait time.Duration // 从提交到开始执行的等待时长
+	Duration  time.Duration // 任务执行耗时，仅在 OnTaskEnd 中有效
+}
+
+// Hook 定义任务执行的回调接口，可用于将指标导出到外部监控系统。
+// 回调在工作协程中同步执行，实现应尽量轻量，且需保证并发安全。
+type Hook interface {
+	// OnTaskStart 在任务开始执行前调用。
+	OnTaskStart(info TaskInfo)
+	// OnTaskEnd 在任务执行结束后调用，err 为任务返回的错误或恢复的 *PanicError。
+	OnTaskEnd(info TaskInfo, err error)
+}
+
+// WithHook 注册任务执行回调，可多次使用以注册多个 Hook，按注册顺序调用。
+func WithHook(h Hook) Option {
+	return func(p *WorkerPool) {
+		if h != nil {
+			p.hooks = append(p.hooks, h)
+		}
+	}
+}
+
+// WithPanicHandler 设置任务 panic 的处理函数。
+// 设置后任务中的 panic 将被恢复并计为失败，工作协程继续运行；
+// 未设置时 panic 将照常向上传播。
+func WithPanicHandler(h func(v any)) Option {
+	return func(p *WorkerPool) {
+		p.panicHandler = h
+	}
+}
+
+// PanicError 表示任务执行期间发生并被恢复的 panic。
+type PanicError struct {
+	Value any    // 原始 panic 值
+	Stack []byte // 发生 panic 时的调用栈
+}
+
+// Error 实现 error 接口。
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("workerpool: task panicked: %v", e.Value)
+}
+
+// Stats 是协程池运行状态的快照。
+type Stats struct {
+	MaxWorkers  int // 当前最大并发工作协程数
+	LiveWorkers int // 存活的工作协程数
+	BusyWorkers int // 正在执行任务的工作协程数
+	IdleWorkers int // 空闲的工作协程数
+	Waiting     int // 等待队列中的任务数
+
+	Submitted uint64 // 已提交的任务数
+	Completed uint64 // 成功完成的任务数
+	Failed    uint64 // 返回错误或发生 panic 的任务数（重试任务仅计最终结果）
+	Retried   uint64 // 失败后按重试策略重新排队的次数
+	Rejected  uint64 // 因协程池已停止而被 TrySubmit 拒绝的任务数
+	Discarded uint64 // 被 Stop 丢弃的排队任务数
+
+	QueueWait HistogramSnapshot // 任务在队列中的等待时间分布
+	ExecTime  HistogramSnapshot // 任务的执行时间分布
+}
+
+// Stats 返回协程池当前运行状态的快照。
+// 各字段分别原子读取，快照之间可能存在微小的不一致。
+func (p *WorkerPool) Stats() Stats {
+	live := int(p.metrics.liveWorkers.Load())
+	busy := int(p.running.Load())
+	return Stats{
+		MaxWorkers:  p.Size(),
+		LiveWorkers: live,
+		BusyWorkers: busy,
+		IdleWorkers: max(live-busy, 0),
+		Waiting:     p.WaitingQueueSize(),
+		Submitted:   p.metrics.submitted.Load(),
+		Completed:   p.metrics.completed.Load(),
+		Failed:      p.metrics.failed.Load(),
+		Retried:     p.metrics.retried.Load(),
+		Rejected:    p.metrics.rejected.Load(),
+		Discarded:   p.metrics.discarded.Load(),
+		QueueWait:   p.metrics.queueWait.snapshot(),
+		ExecTime:    p.metrics.execTime.snapshot(),
+	}
+}
+
+// metrics 汇总协程池的计数器与直方图，所有字段均可并发更新。
+type metrics struct {
+	liveWorkers atomic.Int32
+	submitted   atomic.Uint64
+	completed   atomic.Uint64
+	failed      atomic.Uint64
+	retried     atomic.Uint64
+	rejected    atomic.Uint64
+	discarded   atomic.Uint64
+	queueWait   histogram
+	execTime    histogram
+}
+
+// histogram 是基于 histogramBounds 分桶的无锁直方图。
+type histogram struct {
+	counts [len(histogramBounds) + 1]atomic.Uint64
+	count  atomic.Uint64
+	sum    atomic.Int64
+}
+
+// observe 记录一次观测值。
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for i < len(histogramBounds) && d > histogramBounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+// snapshot 返回直方图当前状态的副本。
+func (h *histogram) snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Bounds: append([]time.Duration(nil), histogramBounds[:]...),
+		Counts: make([]uint64, len(h.counts)),
+		Count:  h.count.Load(),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+	for i := range h.counts {
+		s.Counts[i] = h.counts[i].Load()
+	}
+	return s
+}
+
+// HistogramSnapshot 是耗时直方图的快照。
+// Counts[i] 为落在 (Bounds[i-1], Bounds[i]] 区间内的观测数，
+// 最后一个元素为超过 Bounds 最大值的观测数。
+type HistogramSnapshot struct {
+	Bounds []time.Duration // 各桶的上界（不含溢出桶）
+	Counts []uint64        // 各桶的观测数，长度为 len(Bounds)+1
+	Count  uint64          // 观测总数
+	Sum    time.Duration   // 观测值之和
+}
+
+// Mean 返回观测值的平均数，无观测时返回 0。
+func (s HistogramSnapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Quantile 返回分位数 q（0 < q <= 1）的估计值，即包含该分位的桶的上界。
+// 落在溢出桶时返回 Bounds 的最大值，无观测时返回 0。
+func (s HistogramSnapshot) Quantile(q float64) time.Duration {
+	if s.Count == 0 || len(s.Bounds) == 0 {
+		return 0
+	}
+	rank := max(uint64(math.Ceil(q*float64(s.Count))), 1)
+	var seen uint64
+	for i, c := range s.Counts {
+		seen += c
+		if seen >= rank && i < len(s.Bounds) {
+			return s.Bounds[i]
+		}
+	}
+	return s.Bounds[len(s.Bounds)-1]
+}
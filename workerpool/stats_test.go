@@ -0,0 +1,157 @@
+package workerpool
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingHook 记录回调次数与错误的 Hook，用于测试
+type recordingHook struct {
+	starts atomic.Int32
+	ends   atomic.Int32
+	mu     sync.Mutex
+	errs   []error
+}
+
+func (h *recordingHook) OnTaskStart(info TaskInfo) {
+	h.starts.Add(1)
+}
+
+func (h *recordingHook) OnTaskEnd(info TaskInfo, err error) {
+	h.ends.Add(1)
+	if err != nil {
+		h.mu.Lock()
+		h.errs = append(h.errs, err)
+		h.mu.Unlock()
+	}
+}
+
+// TestStats 测试运行状态快照
+func TestStats(t *testing.T) {
+	pool := New(2)
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	for i := 0; i < 3; i++ {
+		pool.Submit(func() {
+			started <- struct{}{}
+			<-release
+		})
+	}
+	<-started
+	<-started
+	time.Sleep(10 * time.Millisecond)
+
+	s := pool.Stats()
+	if s.MaxWorkers != 2 || s.LiveWorkers != 2 || s.BusyWorkers != 2 || s.IdleWorkers != 0 {
+		t.Errorf("Unexpected worker stats: %+v", s)
+	}
+	if s.Waiting != 1 || s.Submitted != 3 {
+		t.Errorf("Expected 1 waiting and 3 submitted, got %d and %d", s.Waiting, s.Submitted)
+	}
+
+	close(release)
+	pool.SubmitErr(func() error { return errors.New("boom") })
+	pool.StopWait()
+
+	s = pool.Stats()
+	if s.Submitted != 4 || s.Completed != 3 || s.Failed != 1 {
+		t.Errorf("Expected 4 submitted, 3 completed, 1 failed, got %+v", s)
+	}
+	if s.LiveWorkers != 0 {
+		t.Errorf("Expected no live workers after stop, got %d", s.LiveWorkers)
+	}
+	if s.ExecTime.Count != 4 || s.QueueWait.Count != 4 {
+		t.Errorf("Expected 4 observations in histograms, got %d and %d", s.ExecTime.Count, s.QueueWait.Count)
+	}
+	if len(s.ExecTime.Counts) != len(s.ExecTime.Bounds)+1 {
+		t.Errorf("Histogram should have one overflow bucket, got %d counts for %d bounds",
+			len(s.ExecTime.Counts), len(s.ExecTime.Bounds))
+	}
+	if s.ExecTime.Quantile(0.5) <= 0 || s.ExecTime.Mean() <= 0 {
+		t.Error("Histogram quantile and mean should be positive")
+	}
+}
+
+// TestStatsDiscarded 测试 Stop 丢弃的任务计数
+func TestStatsDiscarded(t *testing.T) {
+	pool := New(1)
+	release := make(chan struct{})
+	pool.Submit(func() { <-release })
+	pool.Submit(func() {})
+	pool.Submit(func() {})
+	time.Sleep(10 * time.Millisecond)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	pool.Stop()
+	if d := pool.Stats().Discarded; d != 2 {
+		t.Errorf("Expected 2 discarded tasks, got %d", d)
+	}
+}
+
+// TestHook 测试任务回调
+func TestHook(t *testing.T) {
+	h := &recordingHook{}
+	pool := New(2, WithHook(h))
+	for i := 0; i < 5; i++ {
+		pool.Submit(func() {})
+	}
+	pool.SubmitErr(func() error { return errors.New("failed") })
+	pool.StopWait()
+
+	if h.starts.Load() != 6 || h.ends.Load() != 6 {
+		t.Errorf("Expected 6 start and end callbacks, got %d and %d", h.starts.Load(), h.ends.Load())
+	}
+	if len(h.errs) != 1 || h.errs[0].Error() != "failed" {
+		t.Errorf("Expected one task error, got %v", h.errs)
+	}
+}
+
+// TestPanicHandler 测试任务 panic 的恢复
+func TestPanicHandler(t *testing.T) {
+	var recovered atomic.Value
+	h := &recordingHook{}
+	pool := New(1, WithHook(h), WithPanicHandler(func(v any) { recovered.Store(v) }))
+
+	pool.Submit(func() { panic("oops") })
+	var counter int32
+	pool.SubmitWait(func() { atomic.AddInt32(&counter, 1) })
+	pool.StopWait()
+
+	if recovered.Load() != "oops" {
+		t.Errorf("Panic handler should receive panic value, got %v", recovered.Load())
+	}
+	if counter != 1 {
+		t.Error("Worker should keep running after a recovered panic")
+	}
+	if s := pool.Stats(); s.Failed != 1 {
+		t.Errorf("Panicked task should be counted as failed, got %d", s.Failed)
+	}
+	var pe *PanicError
+	if len(h.errs) != 1 || !errors.As(h.errs[0], &pe) || pe.Value != "oops" {
+		t.Errorf("Hook should receive *PanicError, got %v", h.errs)
+	}
+}
+
+// TestTrySubmit 测试停止后的非 panic 提交
+func TestTrySubmit(t *testing.T) {
+	pool := New(1)
+	done := make(chan struct{})
+	if !pool.TrySubmit(func() { close(done) }) {
+		t.Error("TrySubmit should accept task before stop")
+	}
+	<-done
+	pool.Stop()
+
+	if pool.TrySubmit(func() {}) {
+		t.Error("TrySubmit should reject task after stop")
+	}
+	if r := pool.Stats().Rejected; r != 1 {
+		t.Errorf("Expected 1 rejected task, got %d", r)
+	}
+}
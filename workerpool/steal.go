@@ -0,0 +1,248 @@
+package workerpool
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/wsshow/op/deque"
+)
+
+// WithWorkStealing 启用工作窃取调度模式，适用于大量短小任务的高吞吐场景。
+//
+// 该模式下协程池在创建时启动 maxWorkers 个常驻工作协程，每个工作协程拥有
+// 一个本地双端队列。普通任务提交时直接轮流放入各本地队列，不经过 dispatch 协程；
+// 工作协程从自己队列的头部取任务，本地队列为空时从其他队列的尾部窃取一半任务。
+// 需要限流、处于暂停状态或重试退避中的任务仍先由 dispatch 协程处理，就绪后再放入本地队列。
+//
+// 该模式下工作协程数量固定，Resize、WithMinWorkers、WithIdleTimeout 与
+// WithAdaptiveConcurrency 不再生效；PauseKey 仅对尚未放入本地队列的任务生效。
+func WithWorkStealing() Option {
+	return func(p *WorkerPool) {
+		p.workStealing = true
+	}
+}
+
+// stealQueues 是工作窃取模式下各工作协程的本地队列集合。
+type stealQueues struct {
+	queues []*localQueue
+	next   atomic.Uint32 // 轮流选择本地队列
+	size   atomic.Int32  // 所有本地队列中的任务总数
+	paused func() bool   // 是否暂停取任务
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	idle   atomic.Int32 // 等待任务的工作协程数
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// localQueue 是单个工作协程的本地任务队列。
+type localQueue struct {
+	mu    sync.Mutex
+	tasks deque.Deque[*task]
+}
+
+// newStealQueues 创建 n 个本地队列。
+func newStealQueues(n int, paused func() bool) *stealQueues {
+	s := &stealQueues{
+		queues: make([]*localQueue, n),
+		paused: paused,
+		done:   make(chan struct{}),
+	}
+	for i := range s.queues {
+		s.queues[i] = &localQueue{}
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Len 返回所有本地队列中的任务总数。
+func (s *stealQueues) Len() int {
+	return int(s.size.Load())
+}
+
+// push 将任务轮流放入一个本地队列，并在有工作协程等待时唤醒其中一个。
+// 本地队列已关闭时不放入任务并返回 false。
+func (s *stealQueues) push(t *task) bool {
+	// 持有 mu 放入任务，确保 close 之后工作协程退出前能取到所有已放入的任务
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	q := s.queues[s.next.Add(1)%uint32(len(s.queues))]
+	q.mu.Lock()
+	q.tasks.PushBack(t)
+	q.mu.Unlock()
+	s.size.Add(1)
+	if s.idle.Load() > 0 {
+		s.cond.Signal()
+	}
+	return true
+}
+
+// take 为第 i 个工作协程取出下一个任务，优先本地队列，其次窃取其他队列。
+// 队列关闭且所有任务取完后返回 nil。
+func (s *stealQueues) take(i int) *task {
+	// 队列关闭后暂停失效，工作协程取完剩余任务后退出
+	closed := false
+	for {
+		if closed || !s.paused() {
+			if t := s.queues[i].popFront(); t != nil {
+				s.size.Add(-1)
+				return t
+			}
+			if t := s.steal(i); t != nil {
+				return t
+			}
+			if closed {
+				return nil
+			}
+		}
+
+		s.mu.Lock()
+		s.idle.Add(1)
+		for !s.closed && (s.size.Load() == 0 || s.paused()) {
+			s.cond.Wait()
+		}
+		s.idle.Add(-1)
+		closed = s.closed
+		s.mu.Unlock()
+	}
+}
+
+// steal 从其他本地队列的尾部窃取一半任务，返回其中一个，其余放入第 i 个本地队列。
+func (s *stealQueues) steal(i int) *task {
+	n := len(s.queues)
+	for k := 1; k < n; k++ {
+		victim := s.queues[(i+k)%n]
+		victim.mu.Lock()
+		count := (victim.tasks.Size() + 1) / 2
+		stolen := make([]*task, count)
+		for j := count - 1; j >= 0; j-- {
+			stolen[j] = victim.tasks.PopBack()
+		}
+		victim.mu.Unlock()
+		if count == 0 {
+			continue
+		}
+
+		if count > 1 {
+			own := s.queues[i]
+			own.mu.Lock()
+			for _, t := range stolen[1:] {
+				own.tasks.PushBack(t)
+			}
+			own.mu.Unlock()
+		}
+		s.size.Add(-1)
+		return stolen[0]
+	}
+	return nil
+}
+
+// drain 取出所有本地队列中的任务。
+func (s *stealQueues) drain() []*task {
+	var tasks []*task
+	for _, q := range s.queues {
+		q.mu.Lock()
+		for q.tasks.Size() > 0 {
+			tasks = append(tasks, q.tasks.PopFront())
+		}
+		q.mu.Unlock()
+	}
+	s.size.Add(-int32(len(tasks)))
+	return tasks
+}
+
+// broadcast 唤醒所有等待的工作协程重新检查暂停状态。
+func (s *stealQueues) broadcast() {
+	s.mu.Lock()
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// close 关闭本地队列，工作协程执行完剩余任务后退出。
+// 返回的通道在所有工作协程退出后关闭。
+func (s *stealQueues) close() <-chan struct{} {
+	s.mu.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+	go func() {
+		s.wg.Wait()
+		close(s.done)
+	}()
+	return s.done
+}
+
+// popFront 弹出本地队列头部的任务，队列为空时返回 nil。
+func (q *localQueue) popFront() *task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.tasks.Size() == 0 {
+		return nil
+	}
+	return q.tasks.PopFront()
+}
+
+// startStealing 启动工作窃取模式的工作协程。
+func (p *WorkerPool) startStealing() {
+	for i := range p.steal.queues {
+		p.steal.wg.Add(1)
+		p.metrics.liveWorkers.Add(1)
+		go p.stealWorker(i)
+	}
+}
+
+// stealWorker 是工作窃取模式下第 i 个工作协程的执行函数。
+func (p *WorkerPool) stealWorker(i int) {
+	defer p.steal.wg.Done()
+	defer p.metrics.liveWorkers.Add(-1)
+	for t := p.steal.take(i); t != nil; t = p.steal.take(i) {
+		p.complete(t)
+	}
+}
+
+// stealPaused 返回工作协程是否应暂停从本地队列取任务，协程池开始停止后暂停失效。
+func (p *WorkerPool) stealPaused() bool {
+	select {
+	case <-p.stopSignal:
+		return false
+	default:
+		return p.pause.paused()
+	}
+}
+
+// stopStealing 关闭本地队列并等待工作协程执行完剩余任务后退出，期间回送的任务将被丢弃。
+// 若 Shutdown 在此期间超时，本地队列中剩余的任务将被取出交还给 Shutdown。
+func (p *WorkerPool) stopStealing() {
+	done := p.steal.close()
+	var abort <-chan struct{}
+	if p.waitAll {
+		abort = p.abortSignal
+	}
+	for {
+		select {
+		case <-done:
+			return
+		case task := <-p.requeueChan:
+			p.releaseRequeued(task)
+			p.discard(task)
+		case <-abort:
+			abort = nil
+			select {
+			case <-p.handoffDone:
+			default:
+				p.handOff()
+			}
+		}
+	}
+}
+
+// direct 返回工作窃取模式下任务能否跳过 dispatch 协程直接放入本地队列。
+func (p *WorkerPool) direct(t *task) bool {
+	return !p.limiter.enabled() && !p.pause.paused() && !p.pause.keyPaused(t.key) &&
+		t.notBefore.IsZero()
+}
@@ -0,0 +1,222 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWorkStealing 测试工作窃取模式下所有任务均被执行
+func TestWorkStealing(t *testing.T) {
+	pool := New(4, WithWorkStealing())
+	if s := pool.Stats(); s.LiveWorkers != 4 {
+		t.Errorf("Expected 4 live workers, got %d", s.LiveWorkers)
+	}
+
+	var counter int32
+	for i := 0; i < 10000; i++ {
+		pool.Submit(func() { atomic.AddInt32(&counter, 1) })
+	}
+	pool.StopWait()
+	if counter != 10000 {
+		t.Errorf("Expected 10000 tasks to run, got %d", counter)
+	}
+	if s := pool.Stats(); s.Completed != 10000 || s.LiveWorkers != 0 {
+		t.Errorf("Unexpected stats after StopWait: %+v", s)
+	}
+}
+
+// TestWorkStealingSteal 测试空闲工作协程从繁忙协程的本地队列中窃取任务
+func TestWorkStealingSteal(t *testing.T) {
+	pool := New(2, WithWorkStealing())
+	defer pool.Stop()
+
+	// 直接向第一个本地队列放入任务，只能通过窃取由第二个工作协程并行执行
+	release := make(chan struct{})
+	var running, peak int32
+	var wg sync.WaitGroup
+	wg.Add(2)
+	q := pool.steal.queues[0]
+	q.mu.Lock()
+	for i := 0; i < 2; i++ {
+		q.tasks.PushBack(newTask(wrapTask(func() {
+			defer wg.Done()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&running, -1)
+		})))
+	}
+	q.mu.Unlock()
+	pool.steal.size.Add(2)
+	pool.steal.broadcast()
+
+	waitFor(t, func() bool { return atomic.LoadInt32(&running) == 2 })
+	close(release)
+	wg.Wait()
+	if peak != 2 {
+		t.Errorf("Expected tasks to run in parallel via stealing, peak %d", peak)
+	}
+}
+
+// TestWorkStealingStop 测试工作窃取模式下 Stop 丢弃未执行的任务
+func TestWorkStealingStop(t *testing.T) {
+	pool := New(1, WithWorkStealing())
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	var counter int32
+	for i := 0; i < 5; i++ {
+		pool.Submit(func() { atomic.AddInt32(&counter, 1) })
+	}
+	if n := pool.WaitingQueueSize(); n != 5 {
+		t.Errorf("Expected 5 waiting tasks, got %d", n)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	pool.Stop()
+	if counter != 0 {
+		t.Errorf("Queued tasks should be discarded, got %d", counter)
+	}
+	if s := pool.Stats(); s.Discarded != 5 {
+		t.Errorf("Expected 5 discarded tasks, got %d", s.Discarded)
+	}
+}
+
+// TestWorkStealingSubmitAfterStop 测试工作窃取模式下停止后提交任务触发 panic，而不是丢失任务
+func TestWorkStealingSubmitAfterStop(t *testing.T) {
+	pool := New(2, WithWorkStealing())
+	pool.Stop()
+
+	assertPanics(t, "Submit after Stop should panic", func() {
+		pool.Submit(func() {})
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assertPanics(t, "SubmitWait after Stop should panic", func() {
+			pool.SubmitWait(func() {})
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SubmitWait after Stop should not block")
+	}
+	if pool.TrySubmit(func() {}) {
+		t.Error("TrySubmit after Stop should return false")
+	}
+}
+
+// TestWorkStealingStopWaitPaused 测试工作窃取模式下暂停期间 StopWait 仍执行按键串行的任务
+func TestWorkStealingStopWaitPaused(t *testing.T) {
+	pool := New(2, WithWorkStealing())
+	pool.PauseDispatch()
+	var counter int32
+	for i := 0; i < 2; i++ {
+		pool.SubmitKeyed("k", func() { atomic.AddInt32(&counter, 1) })
+	}
+
+	done := make(chan struct{})
+	go func() {
+		pool.StopWait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("StopWait should not block while dispatch is paused")
+	}
+	if n := atomic.LoadInt32(&counter); n != 2 {
+		t.Errorf("Expected 2 keyed tasks to run, got %d", n)
+	}
+}
+
+// TestWorkStealingFeatures 测试工作窃取模式下按键串行、重试与暂停仍然生效
+func TestWorkStealingFeatures(t *testing.T) {
+	pool := New(4, WithWorkStealing())
+
+	var mu sync.Mutex
+	var order []int
+	for i := 0; i < 20; i++ {
+		pool.SubmitKeyed("k", func() {
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		})
+	}
+
+	var attempts int32
+	err := pool.SubmitRetryWait(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, func() error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Expected success after 3 attempts, got %v after %d", err, attempts)
+	}
+
+	pool.PauseDispatch()
+	var paused int32
+	pool.Submit(func() { atomic.AddInt32(&paused, 1) })
+	time.Sleep(10 * time.Millisecond)
+	if atomic.LoadInt32(&paused) != 0 {
+		t.Error("Task should not run while paused")
+	}
+	pool.Resume()
+	waitFor(t, func() bool { return atomic.LoadInt32(&paused) == 1 })
+
+	pool.StopWait()
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("Keyed tasks should run in order, got %v", order)
+		}
+	}
+	if len(order) != 20 {
+		t.Errorf("Expected 20 keyed tasks, got %d", len(order))
+	}
+}
+
+// TestWorkStealingShutdown 测试工作窃取模式下 Shutdown 超时返回本地队列中的任务
+func TestWorkStealingShutdown(t *testing.T) {
+	pool := New(1, WithWorkStealing())
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	for i := 0; i < 3; i++ {
+		pool.Submit(func() {})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result, err := pool.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if len(result.Pending) != 3 || result.InFlight != 1 {
+		t.Errorf("Expected 3 pending and 1 in-flight, got %d and %d", len(result.Pending), result.InFlight)
+	}
+	close(release)
+	pool.Stop()
+}
@@ -0,0 +1,296 @@
+package workerpool
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// WAL 记录类型
+const (
+	walAppend byte = 1
+	walAck    byte = 2
+)
+
+// walCompactThreshold 是触发日志压缩的最少已确认记录数。
+const walCompactThreshold = 1024
+
+// FileStore 是基于预写日志（write-ahead log）的文件 QueueStore 实现。
+//
+// 每次 Append 都会追加一条记录并调用 fsync，确保任务在提交返回前已落盘；
+// Ack 仅追加确认记录而不强制落盘，崩溃时丢失的确认只会导致任务被重复执行，
+// 不违背至少一次的语义。已确认的记录累积到一定数量后，日志会被压缩为只包含
+// 未确认记录的新文件。打开日志时会截断末尾不完整或校验失败的记录。
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	nextID  uint64
+	pending map[uint64]TaskRecord
+	acked   int // 上次压缩以来的确认数
+}
+
+// NewFileStore 打开或创建 path 处的日志文件，并回放其中的记录。
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, pending: make(map[uint64]TaskRecord)}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s.file = f
+	return s, nil
+}
+
+// Append 实现 QueueStore 接口。
+func (s *FileStore) Append(name string, payload []byte) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return 0, os.ErrClosed
+	}
+	id := s.nextID + 1
+	if _, err := s.file.Write(encodeWALRecord(walAppend, id, name, payload)); err != nil {
+		return 0, err
+	}
+	if err := s.file.Sync(); err != nil {
+		return 0, err
+	}
+	s.nextID = id
+	s.pending[id] = TaskRecord{ID: id, Name: name, Payload: payload}
+	return id, nil
+}
+
+// Ack 实现 QueueStore 接口。
+func (s *FileStore) Ack(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return os.ErrClosed
+	}
+	if _, ok := s.pending[id]; !ok {
+		return nil
+	}
+	if _, err := s.file.Write(encodeWALRecord(walAck, id, "", nil)); err != nil {
+		return err
+	}
+	delete(s.pending, id)
+	s.acked++
+	if s.acked >= walCompactThreshold && s.acked > len(s.pending) {
+		return s.compact()
+	}
+	return nil
+}
+
+// Pending 实现 QueueStore 接口。
+func (s *FileStore) Pending() ([]TaskRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedPending(), nil
+}
+
+// Close 实现 QueueStore 接口。
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// sortedPending 按 ID（即提交顺序）返回未确认的记录。
+func (s *FileStore) sortedPending() []TaskRecord {
+	records := make([]TaskRecord, 0, len(s.pending))
+	for _, r := range s.pending {
+		records = append(records, r)
+	}
+	slices.SortFunc(records, func(a, b TaskRecord) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return records
+}
+
+// replay 回放日志文件，重建未确认记录集合，并截断末尾损坏的记录。
+func (s *FileStore) replay() error {
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		kind, id, name, payload, n, err := decodeWALRecord(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			// 记录不完整或校验失败，视为崩溃时的残缺写入并截断
+			return f.Truncate(offset)
+		}
+		offset += n
+		s.nextID = max(s.nextID, id)
+		switch kind {
+		case walAppend:
+			s.pending[id] = TaskRecord{ID: id, Name: name, Payload: payload}
+		case walAck:
+			delete(s.pending, id)
+		}
+	}
+}
+
+// compact 将未确认的记录写入临时文件并原子替换当前日志。
+// 临时文件以追加模式打开并在替换后直接作为新的日志句柄，替换后无需重新打开文件，
+// 避免继续写入已被替换的旧文件；替换后同步父目录，确保重命名本身已落盘。
+func (s *FileStore) compact() error {
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := writePending(tmp, s.sortedPending()); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	s.file.Close()
+	s.file = tmp
+	s.acked = 0
+	return syncDir(filepath.Dir(s.path))
+}
+
+// writePending 将记录写入 f 并调用 fsync。
+func writePending(f *os.File, records []TaskRecord) error {
+	w := bufio.NewWriter(f)
+	for _, r := range records {
+		if _, err := w.Write(encodeWALRecord(walAppend, r.ID, r.Name, r.Payload)); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// syncDir 对目录调用 fsync，使目录项的变更落盘。Windows 不支持同步目录，直接返回。
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// encodeWALRecord 编码一条日志记录：
+// 类型(1) | ID(8) | 名称长度(uvarint) | 名称 | 参数长度(uvarint) | 参数 | CRC32(4)。
+// 确认记录的名称与参数均为空。
+func encodeWALRecord(kind byte, id uint64, name string, payload []byte) []byte {
+	buf := make([]byte, 0, 1+8+2*binary.MaxVarintLen64+len(name)+len(payload)+4)
+	buf = append(buf, kind)
+	buf = binary.BigEndian.AppendUint64(buf, id)
+	buf = binary.AppendUvarint(buf, uint64(len(name)))
+	buf = append(buf, name...)
+	buf = binary.AppendUvarint(buf, uint64(len(payload)))
+	buf = append(buf, payload...)
+	return binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+}
+
+// errCorruptRecord 表示日志记录校验失败。
+var errCorruptRecord = errors.New("workerpool: corrupt WAL record")
+
+// decodeWALRecord 从 r 中解码一条日志记录，返回记录内容及其占用的字节数。
+// 在记录边界处读到文件末尾时返回 io.EOF，记录不完整时返回 io.ErrUnexpectedEOF。
+func decodeWALRecord(r *bufio.Reader) (kind byte, id uint64, name string, payload []byte, n int64, err error) {
+	crc := crc32.NewIEEE()
+	tr := &countingReader{r: io.TeeReader(r, crc)}
+
+	var head [9]byte
+	if _, err = io.ReadFull(tr, head[:1]); err != nil {
+		return
+	}
+	if _, err = io.ReadFull(tr, head[1:]); err != nil {
+		return 0, 0, "", nil, 0, io.ErrUnexpectedEOF
+	}
+	kind = head[0]
+	id = binary.BigEndian.Uint64(head[1:])
+
+	nameBytes, err := readWALBytes(tr)
+	if err != nil {
+		return 0, 0, "", nil, 0, err
+	}
+	payload, err = readWALBytes(tr)
+	if err != nil {
+		return 0, 0, "", nil, 0, err
+	}
+	sum := crc.Sum32()
+
+	var tail [4]byte
+	if _, err = io.ReadFull(r, tail[:]); err != nil {
+		return 0, 0, "", nil, 0, io.ErrUnexpectedEOF
+	}
+	if binary.BigEndian.Uint32(tail[:]) != sum || (kind != walAppend && kind != walAck) {
+		return 0, 0, "", nil, 0, errCorruptRecord
+	}
+	return kind, id, string(nameBytes), payload, tr.n + 4, nil
+}
+
+// readWALBytes 读取一个以 uvarint 长度为前缀的字节串。
+func readWALBytes(r *countingReader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if size > 1<<30 {
+		return nil, errCorruptRecord
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return b, nil
+}
+
+// countingReader 统计已读取字节数，并为 binary.ReadUvarint 提供 io.ByteReader。
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(c, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
@@ -0,0 +1,206 @@
+package workerpool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// TestFileStoreReopen 测试重新打开日志后恢复未确认的记录
+func TestFileStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	s.Append("a", []byte("1"))
+	id, _ := s.Append("b", []byte("2"))
+	s.Append("c", nil)
+	s.Ack(id)
+	s.Close()
+
+	s, err = NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer s.Close()
+	records, _ := s.Pending()
+	if len(records) != 2 || records[0].Name != "a" || string(records[0].Payload) != "1" || records[1].Name != "c" {
+		t.Errorf("Expected pending [a c], got %+v", records)
+	}
+
+	// 新分配的 ID 不能与已有记录重复
+	next, _ := s.Append("d", nil)
+	if next <= records[1].ID {
+		t.Errorf("Expected new ID greater than %d, got %d", records[1].ID, next)
+	}
+}
+
+// TestFileStoreTruncatedTail 测试截断末尾残缺的记录
+func TestFileStoreTruncatedTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	s, _ := NewFileStore(path)
+	s.Append("a", []byte("first"))
+	s.Append("b", []byte("second"))
+	s.Close()
+
+	// 模拟崩溃时写入了一半的记录
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	records, _ := s.Pending()
+	if len(records) != 1 || records[0].Name != "a" {
+		t.Errorf("Expected only the intact record, got %+v", records)
+	}
+
+	// 截断后追加的记录应能被正常回放
+	s.Append("c", nil)
+	s.Close()
+	s, _ = NewFileStore(path)
+	defer s.Close()
+	records, _ = s.Pending()
+	if len(records) != 2 || records[1].Name != "c" {
+		t.Errorf("Expected [a c] after append, got %+v", records)
+	}
+}
+
+// TestFileStoreCompact 测试已确认记录累积后压缩日志
+func TestFileStoreCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	s, _ := NewFileStore(path)
+	keep, _ := s.Append("keep", []byte("x"))
+	for i := 0; i < walCompactThreshold; i++ {
+		id, _ := s.Append("tmp", make([]byte, 16))
+		if err := s.Ack(id); err != nil {
+			t.Fatalf("Ack failed: %v", err)
+		}
+	}
+	s.Close()
+
+	info, _ := os.Stat(path)
+	if info.Size() > 256 {
+		t.Errorf("Expected compacted log, got %d bytes", info.Size())
+	}
+	s, _ = NewFileStore(path)
+	defer s.Close()
+	records, _ := s.Pending()
+	if len(records) != 1 || records[0].ID != keep {
+		t.Errorf("Expected only the kept record, got %+v", records)
+	}
+}
+
+// TestFileStoreCompactAppend 测试压缩后的写入落到新日志，压缩失败时原日志仍可继续使用
+func TestFileStoreCompactAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	s, _ := NewFileStore(path)
+	compact := func() error {
+		var err error
+		for i := 0; i < walCompactThreshold && err == nil; i++ {
+			id, _ := s.Append("tmp", nil)
+			err = s.Ack(id)
+		}
+		return err
+	}
+
+	// 临时文件路径被目录占用，压缩失败
+	if err := os.Mkdir(path+".tmp", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := compact(); err == nil {
+		t.Error("Expected compaction to fail")
+	}
+	first, err := s.Append("first", nil)
+	if err != nil {
+		t.Fatalf("Append after failed compaction should succeed, got %v", err)
+	}
+
+	os.Remove(path + ".tmp")
+	if err := compact(); err != nil {
+		t.Fatalf("Compaction failed: %v", err)
+	}
+	second, err := s.Append("second", nil)
+	if err != nil {
+		t.Fatalf("Append after compaction failed: %v", err)
+	}
+	s.Close()
+
+	if _, err := os.Stat(path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Temporary file should not remain, got %v", err)
+	}
+	s, _ = NewFileStore(path)
+	defer s.Close()
+	records, _ := s.Pending()
+	if len(records) != 2 || records[0].ID != first || records[1].ID != second {
+		t.Errorf("Expected records written around compaction to survive, got %+v", records)
+	}
+}
+
+// TestFileStoreClosed 测试关闭后的存储拒绝写入
+func TestFileStoreClosed(t *testing.T) {
+	s, _ := NewFileStore(filepath.Join(t.TempDir(), "queue.wal"))
+	s.Close()
+	if _, err := s.Append("a", nil); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected os.ErrClosed, got %v", err)
+	}
+}
+
+// TestFileStoreRecover 测试协程池停止后未执行的命名任务在新协程池中恢复执行
+func TestFileStoreRecover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	store, _ := NewFileStore(path)
+
+	pool := New(1, WithQueueStore(store))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var first atomic.Bool
+	var ran atomic.Int32
+	pool.Register("job", func([]byte) error {
+		if first.CompareAndSwap(false, true) {
+			close(started)
+			<-release
+		}
+		ran.Add(1)
+		return nil
+	})
+	for i := 0; i < 5; i++ {
+		if err := pool.SubmitNamed("job", nil); err != nil {
+			t.Fatalf("SubmitNamed failed: %v", err)
+		}
+	}
+	<-started
+	close(release)
+	pool.Stop()
+	store.Close()
+
+	store, _ = NewFileStore(path)
+	defer store.Close()
+	remaining, _ := store.Pending()
+	if int(ran.Load())+len(remaining) != 5 {
+		t.Errorf("Expected %d tasks left after %d ran, got %d", 5-ran.Load(), ran.Load(), len(remaining))
+	}
+
+	pool = New(2, WithQueueStore(store))
+	pool.Register("job", func([]byte) error {
+		ran.Add(1)
+		return nil
+	})
+	if _, err := pool.Recover(); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	pool.StopWait()
+
+	if ran.Load() != 5 {
+		t.Errorf("Expected 5 executions in total, got %d", ran.Load())
+	}
+	if records, _ := store.Pending(); len(records) != 0 {
+		t.Errorf("Expected all records to be acked, got %+v", records)
+	}
+}
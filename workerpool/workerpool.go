@@ -4,6 +4,8 @@ package workerpool
 
 import (
 	"context"
+	"errors"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -11,6 +13,9 @@ import (
 	"github.com/wsshow/op/deque"
 )
 
+// ErrStopped 表示协程池已停止，任务无法再被提交。
+var ErrStopped = errors.New("workerpool: pool stopped")
+
 // DefaultIdleTimeout 是工作协程的默认空闲超时时间。
 // 若工作协程空闲超过此时间且无新任务到来，该协程将被自动回收。
 const DefaultIdleTimeout = 2 * time.Second
@@ -28,23 +33,115 @@ func WithIdleTimeout(d time.Duration) Option {
 	}
 }
 
+// WithMinWorkers 设置常驻工作协程数量。
+// 协程池启动时即创建 n 个工作协程，且这些协程不受空闲超时回收的影响。
+// n 大于最大并发数时将被截断为最大并发数。
+func WithMinWorkers(n int) Option {
+	return func(p *WorkerPool) {
+		if n > 0 {
+			p.minWorkers = n
+		}
+	}
+}
+
+// task 是协程池内部的任务单元，记录任务函数及其提交时间。
+type task struct {
+	run       func() error
+	submitted time.Time
+	key       string
+	admitted  bool // 已通过限流，可直接分派
+	serial    bool // 按键串行执行，见 SubmitKeyed
+	retry     *retryState
+	notBefore time.Time   // 重试任务再次分派的最早时间
+	record    *TaskRecord // 命名任务的存储记录，见 SubmitNamed
+	discarded func()      // 任务未执行即被丢弃时的回调，见 newTaskWithDiscard
+}
+
+// newTask 创建一个以当前时间为提交时间的任务。
+func newTask(run func() error) *task {
+	return &task{run: run, submitted: time.Now()}
+}
+
+// newTaskWithDiscard 创建一个被丢弃时回调 discarded 的任务，供需要等待任务结束的组件使用。
+// run 与 discarded 最多只有一个生效：任务被丢弃后再执行其函数（例如执行 Shutdown
+// 返回的 PendingTask）将直接返回 ErrStopped。
+func newTaskWithDiscard(run func() error, discarded func()) *task {
+	var settled atomic.Bool
+	t := newTask(func() error {
+		if !settled.CompareAndSwap(false, true) {
+			return ErrStopped
+		}
+		return run()
+	})
+	t.discarded = func() {
+		if settled.CompareAndSwap(false, true) {
+			discarded()
+		}
+	}
+	return t
+}
+
+// wrapTask 将无返回值的任务函数包装为返回 error 的形式。
+func wrapTask(fn func()) func() error {
+	return func() error {
+		fn()
+		return nil
+	}
+}
+
 // WorkerPool 是一个工作协程池，限制并发执行任务的协程数量不超过指定最大值。
 // 当所有工作协程繁忙时，新任务将被放入等待队列。
 // 空闲的工作协程在超过空闲超时时间后会被自动回收。
 type WorkerPool struct {
-	maxWorkers  int
+	maxWorkers  atomic.Int32
+	minWorkers  int
 	idleTimeout time.Duration
 
-	taskChan     chan func()
-	workerChan   chan func()
+	taskChan     chan *task
+	workerChan   chan *task
+	requeueChan  chan *task
+	wakeSignal   chan struct{} // Resize 与暂停状态变化时唤醒 dispatch 协程
 	stopSignal   chan struct{}
 	stoppedChan  chan struct{}
-	waitingQueue deque.Deque[func()]
+	waitingQueue deque.Deque[*task]
+
+	controller Controller
+	adaptEvery time.Duration
+	ceiling    int
+	latency    latencyWindow
+	running    atomic.Int32
+
+	hooks        []Hook
+	panicHandler func(any)
+	metrics      metrics
+	serial       serialKeys
+	pause        pauseState
+	retrying     atomic.Int32
+	settleSignal chan struct{}
+
+	store      QueueStore
+	handlers   map[string]TaskHandler
+	handlersMu sync.RWMutex
+
+	// 以下字段仅由 dispatch 协程访问
+	workerCount int
+	workerWG    sync.WaitGroup
+	limiter     rateLimiter
+	delayed     delayQueue
+	held        heldTasks
+
+	workStealing bool
+	steal        *stealQueues
 
 	stopMutex  sync.Mutex
 	pauseMutex sync.Mutex
 	stopOnce   sync.Once
 
+	abortSignal chan struct{}
+	abortOnce   sync.Once
+	handoffDone chan struct{}
+	handoff     ShutdownResult
+
 	isStopped    bool
 	waitAll      bool
 	waitingCount atomic.Int32
@@ -54,33 +151,82 @@ type WorkerPool struct {
 //
 // maxWorkers 指定最大并发工作协程数，最小值为 1。
 // 若无任务到来，工作协程会在空闲超时后逐渐被回收。
-// 可通过 opts 自定义配置，例如 WithIdleTimeout。
+// 可通过 opts 自定义配置，例如 WithIdleTimeout、WithMinWorkers、
+// WithAdaptiveConcurrency。
 func New(maxWorkers int, opts ...Option) *WorkerPool {
 	if maxWorkers < 1 {
 		maxWorkers = 1
 	}
 
 	pool := &WorkerPool{
-		maxWorkers:  maxWorkers,
-		idleTimeout: DefaultIdleTimeout,
-		taskChan:    make(chan func()),
-		workerChan:  make(chan func()),
-		stopSignal:  make(chan struct{}),
-		stoppedChan: make(chan struct{}),
+		idleTimeout:  DefaultIdleTimeout,
+		taskChan:     make(chan *task),
+		workerChan:   make(chan *task),
+		requeueChan:  make(chan *task),
+		settleSignal: make(chan struct{}, 1),
+		wakeSignal:   make(chan struct{}, 1),
+		stopSignal:   make(chan struct{}),
+		stoppedChan:  make(chan struct{}),
+		abortSignal:  make(chan struct{}),
+		handoffDone:  make(chan struct{}),
 	}
+	pool.maxWorkers.Store(int32(maxWorkers))
 
 	for _, opt := range opts {
 		opt(pool)
 	}
+	if pool.store == nil {
+		pool.store = NewMemoryStore()
+	}
+	pool.minWorkers = min(pool.minWorkers, maxWorkers)
+	pool.ceiling = maxWorkers
+
+	if pool.workStealing {
+		pool.steal = newStealQueues(maxWorkers, pool.stealPaused)
+		pool.startStealing()
+	}
 
 	go pool.dispatch()
+	if pool.controller != nil {
+		go pool.adapt()
+	}
 
 	return pool
 }
 
-// Size 返回最大并发工作协程数。
+// Size 返回当前的最大并发工作协程数。
 func (p *WorkerPool) Size() int {
-	return p.maxWorkers
+	return int(p.maxWorkers.Load())
+}
+
+// Resize 在运行时调整最大并发工作协程数。
+//
+// n 小于常驻工作协程数（见 WithMinWorkers）或小于 1 时将被提升至该下限。
+// 扩容时等待队列中的任务会立即分派给新建的工作协程；
+// 缩容时不会中断正在执行的任务，多余的工作协程会在完成当前任务后退出。
+// 工作窃取模式（见 WithWorkStealing）下工作协程数固定，Resize 不做任何调整，
+// WithAdaptiveConcurrency 同样不会生效。
+func (p *WorkerPool) Resize(n int) {
+	if p.steal != nil {
+		return
+	}
+	n = max(n, p.minWorkers, 1)
+	if int(p.maxWorkers.Swap(int32(n))) == n {
+		return
+	}
+	// 唤醒 dispatch 协程以重新评估工作协程数量
+	p.wake()
+}
+
+// wake 唤醒 dispatch 协程重新检查工作协程数量与暂停状态。
+func (p *WorkerPool) wake() {
+	select {
+	case p.wakeSignal <- struct{}{}:
+	default:
+	}
+	if p.steal != nil {
+		p.steal.broadcast()
+	}
 }
 
 // Stop 停止工作协程池，仅等待当前运行的任务完成。
@@ -109,10 +255,30 @@ func (p *WorkerPool) Stopped() bool {
 // task 为 nil 时将被忽略。协程池停止后调用将触发 panic。
 func (p *WorkerPool) Submit(task func()) {
 	if task != nil {
-		p.taskChan <- task
+		p.submit(newTask(wrapTask(task)))
 	}
 }
 
+// SubmitErr 提交一个返回 error 的任务到协程池中执行。
+//
+// 任务返回的非 nil 错误会计入 Stats 的失败计数，并传递给 Hook.OnTaskEnd。
+// 除此之外与 Submit 行为一致。task 为 nil 时将被忽略。
+func (p *WorkerPool) SubmitErr(task func() error) {
+	if task != nil {
+		p.submit(newTask(task))
+	}
+}
+
+// TrySubmit 尝试将任务提交到协程池中执行。
+// 与 Submit 不同，协程池停止后调用不会触发 panic，而是返回 false 并计入拒绝数。
+// task 为 nil 时将被忽略并返回 true。
+func (p *WorkerPool) TrySubmit(task func()) bool {
+	if task == nil {
+		return true
+	}
+	return p.trySubmit(newTask(wrapTask(task)))
+}
+
 // SubmitWait 将任务提交到协程池并阻塞等待其执行完成。
 // task 为 nil 时立即返回。
 func (p *WorkerPool) SubmitWait(task func()) {
@@ -120,23 +286,49 @@ func (p *WorkerPool) SubmitWait(task func()) {
 		return
 	}
 	doneChan := make(chan struct{})
-	p.taskChan <- func() {
+	p.submit(newTask(func() error {
 		defer close(doneChan)
 		task()
-	}
+		return nil
+	}))
 	<-doneChan
 }
 
-// WaitingQueueSize 返回等待队列中的任务数量。
+// trySubmit 在协程池未停止时提交任务，否则计入拒绝数并返回 false。
+func (p *WorkerPool) trySubmit(t *task) bool {
+	p.stopMutex.Lock()
+	defer p.stopMutex.Unlock()
+	if p.isStopped {
+		p.metrics.rejected.Add(1)
+		return false
+	}
+	p.submit(t)
+	return true
+}
+
+// submit 将任务发送给 dispatch 协程并计入提交数。
+// 工作窃取模式下本地队列已关闭时改走任务通道，与普通模式一样在协程池停止后触发 panic。
+func (p *WorkerPool) submit(t *task) {
+	if p.steal == nil || !p.direct(t) || !p.steal.push(t) {
+		p.taskChan <- t
+	}
+	p.metrics.submitted.Add(1)
+}
+
+// WaitingQueueSize 返回等待队列中的任务数量，包括被限流延迟和按键串行等待的任务。
 func (p *WorkerPool) WaitingQueueSize() int {
-	return int(p.waitingCount.Load())
+	n := p.waitingCount.Load() + p.serial.pending.Load()
+	if p.steal != nil {
+		n += int32(p.steal.Len())
+	}
+	return int(n)
 }
 
-// Pause 暂停协程池中所有工作协程的任务执行。
+// Pause 暂停协程池的任务分派，阻塞直到 ctx 取消或超时后自动恢复。
 //
-// 调用后将阻塞直到所有工作协程进入暂停状态。暂停期间提交的新任务
-// 将被放入等待队列，待 ctx 取消或超时后恢复执行。
-// 若协程池已处于暂停状态，本次调用将等待前一次暂停结束后再执行。
+// 正在执行的任务不受影响，暂停期间提交的新任务将被放入等待队列，
+// 待暂停结束后恢复执行。暂停不占用工作协程，非阻塞的暂停见 PauseDispatch。
+// 若协程池已处于 Pause 发起的暂停状态，本次调用将等待前一次暂停结束后再执行。
 func (p *WorkerPool) Pause(ctx context.Context) {
 	p.pauseMutex.Lock()
 	defer p.pauseMutex.Unlock()
@@ -148,26 +340,14 @@ func (p *WorkerPool) Pause(ctx context.Context) {
 	}
 	p.stopMutex.Unlock()
 
-	// 提交占位任务以阻塞所有 worker
-	readyWG := new(sync.WaitGroup)
-	doneWG := new(sync.WaitGroup)
-	readyWG.Add(p.maxWorkers)
-	doneWG.Add(p.maxWorkers)
-
-	for i := 0; i < p.maxWorkers; i++ {
-		p.Submit(func() {
-			readyWG.Done()
-			defer doneWG.Done()
-			select {
-			case <-ctx.Done():
-			case <-p.stopSignal:
-			}
-		})
+	p.pause.holds.Add(1)
+	p.wake()
+	select {
+	case <-ctx.Done():
+	case <-p.stopSignal:
 	}
-
-	readyWG.Wait() // 等待所有暂停任务开始执行
-	<-ctx.Done()   // 等待 context 取消
-	doneWG.Wait()  // 等待所有暂停任务完成
+	p.pause.holds.Add(-1)
+	p.wake()
 }
 
 // dispatch 是任务分发器的主循环，运行在独立的 goroutine 中。
@@ -177,15 +357,27 @@ func (p *WorkerPool) dispatch() {
 	timeout := time.NewTimer(p.idleTimeout)
 	defer timeout.Stop()
 
-	var (
-		workerCount int
-		idle        bool
-		wg          sync.WaitGroup
-	)
+	// 预先启动常驻工作协程，工作窃取模式下由 startStealing 启动
+	for p.steal == nil && p.workerCount < p.minWorkers {
+		p.startWorker(nil)
+	}
+
+	var idle bool
 
 dispatchLoop:
 	for {
-		if p.waitingQueue.Size() > 0 {
+		if p.workerCount > p.Size() {
+			if !p.shrink() {
+				break dispatchLoop
+			}
+			continue
+		}
+
+		if p.pause.resumed.Swap(false) {
+			p.releaseHeld(false)
+		}
+
+		if p.waitingQueue.Size() > 0 && !p.pause.paused() {
 			if !p.processWaitingQueue() {
 				break dispatchLoop
 			}
@@ -197,7 +389,7 @@ dispatchLoop:
 			if !ok {
 				break dispatchLoop
 			}
-			p.handleTask(task, &workerCount, &wg)
+			p.handleTask(task)
 			idle = false
 			// 收到新任务后重置空闲计时器，确保超时时间一致
 			if !timeout.Stop() {
@@ -207,71 +399,216 @@ dispatchLoop:
 				}
 			}
 			timeout.Reset(p.idleTimeout)
+		case task := <-p.requeueChan:
+			p.acceptRequeued(task)
+		case <-p.wakeSignal:
+		case <-p.delayed.C():
+			p.promoteDelayed()
 		case <-timeout.C:
-			if idle && workerCount > 0 {
+			if idle && p.workerCount > p.minWorkers {
 				if p.killIdleWorker() {
-					workerCount--
+					p.workerCount--
 				}
 			}
+			p.limiter.prune(time.Now())
 			idle = true
 			timeout.Reset(p.idleTimeout)
 		}
 	}
 
-	if p.waitAll {
-		p.runQueuedTasks()
+	if !p.waitAll {
+		for _, t := range p.drainQueued() {
+			p.discard(t)
+		}
+	} else if !p.runQueuedTasks() {
+		p.handOff()
+	}
+	if p.steal != nil {
+		p.stopStealing()
 	}
 
-	// 停止所有剩余工作协程
-	for workerCount > 0 {
-		p.workerChan <- nil
-		workerCount--
+	// 停止所有剩余工作协程，期间仍在执行的任务回送的后续任务将被丢弃；
+	// Shutdown 期限到达时立即转交结果，不再等待仍在执行的任务
+	var abort <-chan struct{}
+	if p.waitAll {
+		abort = p.abortSignal
+	}
+	for p.workerCount > 0 {
+		select {
+		case p.workerChan <- nil:
+			p.workerCount--
+		case task := <-p.requeueChan:
+			p.releaseRequeued(task)
+			p.discard(task)
+		case <-abort:
+			abort = nil
+			select {
+			case <-p.handoffDone:
+			default:
+				p.handOff()
+			}
+		}
 	}
-	wg.Wait()
+	p.workerWG.Wait()
 }
 
 // handleTask 将任务分配给可用的工作协程，或创建新协程，或加入等待队列。
-func (p *WorkerPool) handleTask(task func(), workerCount *int, wg *sync.WaitGroup) {
+// 需要限流或处于暂停状态的任务统一进入等待队列，由 processWaitingQueue 在分派前检查。
+func (p *WorkerPool) handleTask(task *task) {
+	if p.needsAdmission(task) || p.pause.paused() || p.pause.keyPaused(task.key) {
+		p.waitingQueue.PushBack(task)
+		p.updateWaiting()
+		return
+	}
+	if p.steal != nil {
+		p.steal.push(task)
+		return
+	}
 	select {
 	case p.workerChan <- task:
 	default:
-		if *workerCount < p.maxWorkers {
-			wg.Add(1)
-			go worker(task, p.workerChan, wg)
-			*workerCount++
+		if p.workerCount < p.Size() {
+			p.startWorker(task)
 		} else {
 			p.waitingQueue.PushBack(task)
-			p.waitingCount.Store(int32(p.waitingQueue.Size()))
+			p.updateWaiting()
 		}
 	}
 }
 
+// startWorker 启动一个新的工作协程并执行 t。
+// t 为 nil 时工作协程以空闲状态启动，等待分派任务。
+func (p *WorkerPool) startWorker(t *task) {
+	p.workerWG.Add(1)
+	p.workerCount++
+	p.metrics.liveWorkers.Add(1)
+	go p.worker(t)
+}
+
 // worker 是工作协程的执行函数。
 // 持续从 workerChan 接收并执行任务，收到 nil 时退出。
-func worker(task func(), workerChan chan func(), wg *sync.WaitGroup) {
-	defer wg.Done()
-	for task != nil {
-		task()
-		task = <-workerChan
+func (p *WorkerPool) worker(t *task) {
+	defer p.workerWG.Done()
+	defer p.metrics.liveWorkers.Add(-1)
+	if t == nil {
+		t = <-p.workerChan
+	}
+	for t != nil {
+		p.complete(t)
+		t = <-p.workerChan
+	}
+}
+
+// complete 执行任务，并将需要重试的任务或同一键的后续任务回送 dispatch 协程。
+func (p *WorkerPool) complete(t *task) {
+	if p.execute(t) {
+		p.requeueChan <- t
+	} else if t.serial {
+		if next := p.serial.next(t.key); next != nil {
+			p.requeueChan <- next
+		}
 	}
 }
 
+// execute 执行任务，记录统计指标并回调 Hook。
+// 返回 true 表示任务失败且需按重试策略重新排队。
+func (p *WorkerPool) execute(t *task) bool {
+	info := TaskInfo{Submitted: t.submitted, Started: time.Now()}
+	info.QueueWait = info.Started.Sub(t.submitted)
+	p.metrics.queueWait.observe(info.QueueWait)
+	p.running.Add(1)
+	for _, h := range p.hooks {
+		h.OnTaskStart(info)
+	}
+
+	err := p.run(t)
+
+	info.Duration = time.Since(info.Started)
+	p.running.Add(-1)
+	p.metrics.execTime.observe(info.Duration)
+	if p.controller != nil {
+		p.latency.record(info.Duration)
+	}
+	requeue := err != nil && t.retry != nil && t.retry.next(err)
+	switch {
+	case requeue:
+		p.metrics.retried.Add(1)
+	case err != nil:
+		p.metrics.failed.Add(1)
+	default:
+		p.metrics.completed.Add(1)
+	}
+	for _, h := range p.hooks {
+		h.OnTaskEnd(info, err)
+	}
+
+	if requeue {
+		t.admitted = false
+		t.notBefore = time.Now().Add(t.retry.backoff())
+	} else if t.retry != nil {
+		t.retry.finish(err)
+		p.retrying.Add(-1)
+		select {
+		case p.settleSignal <- struct{}{}:
+		default:
+		}
+	}
+	return requeue
+}
+
+// run 调用任务函数。若设置了 panic 处理函数，任务中的 panic 将被恢复并转换为 *PanicError。
+func (p *WorkerPool) run(t *task) (err error) {
+	if p.panicHandler != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+				p.panicHandler(r)
+			}
+		}()
+	}
+	return t.run()
+}
+
 // stop 执行协程池的停止操作。wait 为 true 时等待所有排队任务完成。
 func (p *WorkerPool) stop(wait bool) {
+	p.beginStop(wait)
+	<-p.stoppedChan
+}
+
+// beginStop 拒绝新的任务提交并通知 dispatch 协程开始停止，不等待停止完成。
+func (p *WorkerPool) beginStop(wait bool) {
 	p.stopOnce.Do(func() {
 		close(p.stopSignal)
+		if p.steal != nil {
+			// 停止开始后暂停失效，唤醒因暂停而等待的工作协程
+			p.steal.broadcast()
+		}
 		p.stopMutex.Lock()
 		p.isStopped = true
 		p.waitAll = wait
 		p.stopMutex.Unlock()
 		close(p.taskChan)
 	})
-	<-p.stoppedChan
 }
 
 // processWaitingQueue 处理等待队列：接收新任务或将队首任务分派给工作协程。
+// 若扩容后工作协程数未达上限，则直接为队首任务创建新协程。
 // 返回 false 表示任务通道已关闭，协程池应停止。
 func (p *WorkerPool) processWaitingQueue() bool {
+	if p.holdFront() || !p.admitFront() {
+		return true
+	}
+	if p.steal != nil {
+		p.steal.push(p.waitingQueue.PopFront())
+		p.updateWaiting()
+		return true
+	}
+	if p.workerCount < p.Size() {
+		p.startWorker(p.waitingQueue.PopFront())
+		p.updateWaiting()
+		return true
+	}
+
 	select {
 	case task, ok := <-p.taskChan:
 		if !ok {
@@ -280,11 +617,106 @@ func (p *WorkerPool) processWaitingQueue() bool {
 		p.waitingQueue.PushBack(task)
 	case p.workerChan <- p.waitingQueue.Front():
 		p.waitingQueue.PopFront()
+	case task := <-p.requeueChan:
+		p.acceptRequeued(task)
+	case <-p.wakeSignal:
+	case <-p.delayed.C():
+		p.promoteDelayed()
+	}
+	p.updateWaiting()
+	return true
+}
+
+// shrink 在工作协程数超出上限时回收下一个完成任务的工作协程。
+// 等待期间到来的新任务会被放入等待队列。
+// 返回 false 表示任务通道已关闭，协程池应停止。
+func (p *WorkerPool) shrink() bool {
+	select {
+	case task, ok := <-p.taskChan:
+		if !ok {
+			return false
+		}
+		p.waitingQueue.PushBack(task)
+		p.updateWaiting()
+	case p.workerChan <- nil:
+		p.workerCount--
+	case task := <-p.requeueChan:
+		p.acceptRequeued(task)
+	case <-p.wakeSignal:
+	case <-p.delayed.C():
+		p.promoteDelayed()
 	}
-	p.waitingCount.Store(int32(p.waitingQueue.Size()))
 	return true
 }
 
+// needsAdmission 返回任务在分派前是否需要经过限流检查。
+func (p *WorkerPool) needsAdmission(t *task) bool {
+	return p.limiter.enabled() && !t.admitted
+}
+
+// admitFront 对队首任务进行限流检查。
+// 配额不足时任务被移入延迟队列并返回 false，否则返回 true。
+func (p *WorkerPool) admitFront() bool {
+	front := p.waitingQueue.Front()
+	if !p.needsAdmission(front) {
+		return true
+	}
+	now := time.Now()
+	front.admitted = true
+	if wait := p.limiter.reserve(front, now); wait > 0 {
+		p.delayed.push(p.waitingQueue.PopFront(), now.Add(wait))
+		p.updateWaiting()
+		return false
+	}
+	return true
+}
+
+// promoteDelayed 将延迟队列中已就绪的任务按顺序放回等待队列头部。
+func (p *WorkerPool) promoteDelayed() {
+	ready := p.delayed.popReady(time.Now())
+	for i := len(ready) - 1; i >= 0; i-- {
+		p.waitingQueue.PushFront(ready[i])
+	}
+	p.updateWaiting()
+}
+
+// discard 丢弃未执行的任务并计入丢弃数，重试任务以 ErrStopped 结束，并回调任务的丢弃通知。
+func (p *WorkerPool) discard(t *task) {
+	p.metrics.discarded.Add(1)
+	if t.retry != nil {
+		t.retry.finish(ErrStopped)
+		p.retrying.Add(-1)
+	}
+	if t.discarded != nil {
+		t.discarded()
+	}
+}
+
+// acceptRequeued 接收工作协程回送的任务并放入等待队列尾部。
+// 处于退避期的重试任务放入延迟队列，到期后再进入等待队列。
+func (p *WorkerPool) acceptRequeued(t *task) {
+	p.releaseRequeued(t)
+	if now := time.Now(); t.notBefore.After(now) {
+		p.delayed.push(t, t.notBefore)
+	} else {
+		p.waitingQueue.PushBack(t)
+	}
+	p.updateWaiting()
+}
+
+// releaseRequeued 在 dispatch 协程收到回送任务后更新相应的等待计数。
+// 计数在收到任务时才扣减，确保 StopWait 不会遗漏正在回送途中的任务。
+func (p *WorkerPool) releaseRequeued(t *task) {
+	if t.serial {
+		p.serial.pending.Add(-1)
+	}
+}
+
+// updateWaiting 更新等待任务计数，包括等待队列、延迟队列与因键暂停而搁置的任务。
+func (p *WorkerPool) updateWaiting() {
+	p.waitingCount.Store(int32(p.waitingQueue.Size() + p.delayed.Len() + p.held.count))
+}
+
 // killIdleWorker 向工作协程通道发送 nil 以回收一个空闲协程。
 func (p *WorkerPool) killIdleWorker() bool {
 	select {
@@ -295,10 +727,69 @@ func (p *WorkerPool) killIdleWorker() bool {
 	}
 }
 
-// runQueuedTasks 将等待队列中的所有任务依次分派给工作协程执行。
-func (p *WorkerPool) runQueuedTasks() {
+// runQueuedTasks 将等待队列、延迟队列、按键串行等待以及尚未结束重试的所有任务
+// 依次分派给工作协程执行。返回 false 表示 Shutdown 超时，排空被中止。
+func (p *WorkerPool) runQueuedTasks() bool {
+	// 协程池停止时暂停失效
+	p.releaseHeld(true)
+	for p.waitingQueue.Size() > 0 || p.delayed.Len() > 0 ||
+		p.serial.pending.Load() > 0 || p.retrying.Load() > 0 {
+		select {
+		case <-p.abortSignal:
+			return false
+		default:
+		}
+		if p.waitingQueue.Size() == 0 {
+			// 等待延迟任务就绪、正在执行的任务回送后续任务或重试任务结束
+			select {
+			case <-p.delayed.C():
+				p.promoteDelayed()
+			case task := <-p.requeueChan:
+				p.acceptRequeued(task)
+			case <-p.settleSignal:
+			case <-p.abortSignal:
+				return false
+			}
+			continue
+		}
+		if !p.admitFront() {
+			continue
+		}
+		if p.steal != nil {
+			p.steal.push(p.waitingQueue.PopFront())
+			p.updateWaiting()
+			continue
+		}
+		if p.workerCount < p.Size() {
+			p.startWorker(p.waitingQueue.PopFront())
+			p.updateWaiting()
+			continue
+		}
+		select {
+		case p.workerChan <- p.waitingQueue.Front():
+			p.waitingQueue.PopFront()
+		case task := <-p.requeueChan:
+			p.acceptRequeued(task)
+		case <-p.abortSignal:
+			return false
+		}
+		p.updateWaiting()
+	}
+	return true
+}
+
+// drainQueued 取出等待队列、延迟队列、暂停搁置与按键串行等待中的全部任务。
+func (p *WorkerPool) drainQueued() []*task {
+	tasks := make([]*task, 0, p.waitingQueue.Size()+p.delayed.Len())
 	for p.waitingQueue.Size() > 0 {
-		p.workerChan <- p.waitingQueue.PopFront()
-		p.waitingCount.Store(int32(p.waitingQueue.Size()))
+		tasks = append(tasks, p.waitingQueue.PopFront())
+	}
+	tasks = append(tasks, p.delayed.drain()...)
+	tasks = append(tasks, p.held.release(func(string) bool { return true })...)
+	tasks = append(tasks, p.serial.drain()...)
+	if p.steal != nil {
+		tasks = append(tasks, p.steal.drain()...)
 	}
+	p.updateWaiting()
+	return tasks
 }
@@ -0,0 +1,85 @@
+package workerpool
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// benchmarkModes 列出参与对比的调度模式
+var benchmarkModes = []struct {
+	name string
+	opts []Option
+}{
+	{"Dispatcher", nil},
+	{"WorkStealing", []Option{WithWorkStealing()}},
+}
+
+// BenchmarkSubmitTiny 测试单个提交者提交大量微小任务的吞吐量
+func BenchmarkSubmitTiny(b *testing.B) {
+	for _, mode := range benchmarkModes {
+		b.Run(mode.name, func(b *testing.B) {
+			pool := New(runtime.GOMAXPROCS(0), mode.opts...)
+			var wg sync.WaitGroup
+			wg.Add(b.N)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pool.Submit(wg.Done)
+			}
+			wg.Wait()
+			b.StopTimer()
+			pool.Stop()
+		})
+	}
+}
+
+// BenchmarkSubmitParallel 测试多个提交者并发提交微小任务的吞吐量
+func BenchmarkSubmitParallel(b *testing.B) {
+	for _, mode := range benchmarkModes {
+		b.Run(mode.name, func(b *testing.B) {
+			pool := New(runtime.GOMAXPROCS(0), mode.opts...)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					pool.Submit(func() {})
+				}
+			})
+			pool.StopWait()
+		})
+	}
+}
+
+// BenchmarkSubmitWork 测试不同任务计算量下两种调度模式的吞吐量
+func BenchmarkSubmitWork(b *testing.B) {
+	for _, work := range []int{10, 1000} {
+		for _, mode := range benchmarkModes {
+			b.Run(fmt.Sprintf("%s-%d", mode.name, work), func(b *testing.B) {
+				pool := New(runtime.GOMAXPROCS(0), mode.opts...)
+				var wg sync.WaitGroup
+				wg.Add(b.N)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					pool.Submit(func() {
+						if spin(work) < 0 {
+							b.Error("unexpected result")
+						}
+						wg.Done()
+					})
+				}
+				wg.Wait()
+				b.StopTimer()
+				pool.Stop()
+			})
+		}
+	}
+}
+
+// spin 执行 n 次简单计算以模拟任务负载。
+func spin(n int) int {
+	x := 0
+	for i := 0; i < n; i++ {
+		x += i & 7
+	}
+	return x
+}
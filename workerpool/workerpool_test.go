@@ -192,8 +192,8 @@ func TestPause(t *testing.T) {
 	wg.Wait()
 	<-pauseDone                       // 等待 Pause 返回
 	time.Sleep(50 * time.Millisecond) // 等待第三个任务完成
-	if counter != 3 {
-		t.Errorf("All tasks should complete after pause, expected counter 3, got %d", counter)
+	if n := atomic.LoadInt32(&counter); n != 3 {
+		t.Errorf("All tasks should complete after pause, expected counter 3, got %d", n)
 	}
 }
 
@@ -259,8 +259,101 @@ func TestMultiplePause(t *testing.T) {
 	cancel1()                         // 取消第一个 Pause
 	<-pause2Done                      // 等待第二个 Pause 完成
 	time.Sleep(20 * time.Millisecond) // 等待任务执行
+	if n := atomic.LoadInt32(&counter); n != 1 {
+		t.Errorf("Task should complete after pauses end, expected counter 1, got %d", n)
+	}
+}
+
+// TestResize 测试运行时调整最大并发数
+func TestResize(t *testing.T) {
+	pool := New(1)
+	defer pool.Stop()
+
+	var running, peak int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for i := 0; i < 4; i++ {
+		pool.Submit(func() {
+			defer wg.Done()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&running, -1)
+		})
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if size := pool.WaitingQueueSize(); size != 3 {
+		t.Errorf("Expected 3 queued tasks before resize, got %d", size)
+	}
+
+	// 扩容后排队任务应立即获得工作协程
+	pool.Resize(4)
+	if pool.Size() != 4 {
+		t.Errorf("Resize(4) should set size to 4, got %d", pool.Size())
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := atomic.LoadInt32(&running); n != 4 {
+		t.Errorf("Expected 4 running tasks after grow, got %d", n)
+	}
+
+	// 缩容不会中断正在运行的任务
+	pool.Resize(2)
+	close(release)
+	wg.Wait()
+
+	atomic.StoreInt32(&peak, 0)
+	block := make(chan struct{})
+	wg.Add(6)
+	for i := 0; i < 6; i++ {
+		pool.Submit(func() {
+			defer wg.Done()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-block
+			atomic.AddInt32(&running, -1)
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(block)
+	wg.Wait()
+	if p := atomic.LoadInt32(&peak); p > 2 {
+		t.Errorf("Expected at most 2 concurrent tasks after shrink, got %d", p)
+	}
+}
+
+// TestMinWorkers 测试常驻工作协程下限
+func TestMinWorkers(t *testing.T) {
+	pool := New(4, WithMinWorkers(2), WithIdleTimeout(10*time.Millisecond))
+	defer pool.Stop()
+
+	pool.Resize(0)
+	if pool.Size() != 2 {
+		t.Errorf("Resize below min workers should clamp to 2, got %d", pool.Size())
+	}
+
+	pool = New(2, WithMinWorkers(5))
+	defer pool.Stop()
+	pool.Resize(1)
+	if pool.Size() != 2 {
+		t.Errorf("Min workers should be capped at max workers, expected size 2, got %d", pool.Size())
+	}
+
+	var counter int32
+	pool.SubmitWait(func() { atomic.AddInt32(&counter, 1) })
 	if counter != 1 {
-		t.Errorf("Task should complete after pauses end, expected counter 1, got %d", counter)
+		t.Errorf("Pool with min workers should execute tasks, got %d", counter)
 	}
 }
 